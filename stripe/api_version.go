@@ -0,0 +1,51 @@
+package stripe
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// apiVersionBasil is the first API version that moves fields the stripe-go
+// structs decode, such as the current period of subscriptions to their items.
+// Tables decode the moved fields from the raw response themselves.
+const apiVersionBasil = "2025-03-31"
+
+// apiVersion returns the API version requests are made with. Unless the
+// connection sets api_version, this is the version stripe-go is pinned to.
+func apiVersion(d *plugin.QueryData) string {
+	stripeConfig := GetConfig(d.Connection)
+	if stripeConfig.APIVersion != nil && *stripeConfig.APIVersion != "" {
+		return *stripeConfig.APIVersion
+	}
+	return stripe.APIVersion
+}
+
+// apiVersionAtLeast reports whether requests are made with the given API
+// version or a later one. It relies on plain string comparison: API versions
+// start with their release date as YYYY-MM-DD, so a later version sorts
+// after an earlier one, and a release name suffix such as .basil only orders
+// versions released on the same day.
+func apiVersionAtLeast(d *plugin.QueryData, version string) bool {
+	return apiVersion(d) >= version
+}
+
+func getApiVersion(_ context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	return apiVersion(d), nil
+}
+
+// apiVersionTransport overrides the Stripe-Version header that stripe-go sets
+// on every request.
+type apiVersionTransport struct {
+	version string
+	base    http.RoundTripper
+}
+
+func (t *apiVersionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Stripe-Version", t.version)
+	return t.base.RoundTrip(req)
+}
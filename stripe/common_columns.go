@@ -2,6 +2,8 @@ package stripe
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/stripe/stripe-go/v76"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
@@ -14,18 +16,73 @@ func commonColumns(c []*plugin.Column) []*plugin.Column {
 	return append([]*plugin.Column{
 		{
 			Name:        "account_id",
-			Description: "The Stripe account ID.",
+			Description: "The Stripe account ID. For connections with connected_account_ids, this is the connected account the row belongs to.",
 			Type:        proto.ColumnType_STRING,
 			Hydrate:     getAccountId,
 			Transform:   transform.FromValue(),
 		},
+		{
+			Name:        "platform_account_id",
+			Description: "The ID of the Stripe account that owns the API key.",
+			Type:        proto.ColumnType_STRING,
+			Hydrate:     getPlatformAccountId,
+			Transform:   transform.FromValue(),
+		},
+		{
+			Name:        "livemode",
+			Description: "True if the row was retrieved with a live mode API key, false if with a test mode API key.",
+			Type:        proto.ColumnType_BOOL,
+			Hydrate:     getLivemode,
+			Transform:   transform.FromValue(),
+		},
+		{
+			Name:        "api_version",
+			Description: "The Stripe API version the row was retrieved with.",
+			Type:        proto.ColumnType_STRING,
+			Hydrate:     getApiVersion,
+			Transform:   transform.FromValue(),
+		},
 	}, c...)
 }
 
+// getLivemode returns whether the API key of the connection is a live mode
+// key. Live mode keys are prefixed sk_live_ or rk_live_ and test mode keys
+// sk_test_ or rk_test_, and objects are only visible to keys of their mode.
+func getLivemode(_ context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	return isLivemode(d), nil
+}
+
+func isLivemode(d *plugin.QueryData) bool {
+	return strings.Contains(getAPIKey(d), "_live_")
+}
+
 // if the caching is required other than per connection, build a cache key for the call and use it in Memoize.
 var getAccountMemoized = plugin.HydrateFunc(getAccountUncached).Memoize(memoize.WithCacheKeyFunction(getAccountCacheKey))
 
 func getAccountId(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	// Rows listed through a connected account belong to that account
+	if id := connectedAccount(ctx); id != nil {
+		return *id, nil
+	}
+	return getPlatformAccountId(ctx, d, h)
+}
+
+// The account that owns an API key never changes, and Steampipe clears the
+// connection cache when the connection config changes, so the platform
+// account ID is cached far longer than the account. Pruning connections on
+// account_id then only calls the API the first time a connection is queried.
+var getPlatformAccountIdMemoized = plugin.HydrateFunc(getPlatformAccountIdUncached).Memoize(memoize.WithCacheKeyFunction(getPlatformAccountIdCacheKey), memoize.WithTtl(24*time.Hour))
+
+func getPlatformAccountId(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	return getPlatformAccountIdMemoized(ctx, d, h)
+}
+
+func getPlatformAccountIdCacheKey(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	key := "getPlatformAccountId"
+	return key, nil
+}
+
+func getPlatformAccountIdUncached(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
 	acc, err := getAccountMemoized(ctx, d, h)
 	if err != nil {
 		return nil, err
@@ -5,7 +5,18 @@ import (
 )
 
 type stripeConfig struct {
-	APIKey *string `hcl:"api_key"`
+	APIKey                 *string  `hcl:"api_key"`
+	APIVersion             *string  `hcl:"api_version"`
+	ConnectedAccountIds    []string `hcl:"connected_account_ids,optional"`
+	APIBaseURL             *string  `hcl:"api_base_url"`
+	UploadsBaseURL         *string  `hcl:"uploads_base_url"`
+	IgnorePermissionErrors *bool    `hcl:"ignore_permission_errors"`
+	ListSlices             *int     `hcl:"list_slices"`
+	MaxConcurrentSlices    *int     `hcl:"max_concurrent_slices"`
+	SliceUnboundedLists    *bool    `hcl:"slice_unbounded_lists"`
+	MaxRetries             *int     `hcl:"max_retries"`
+	RequestsPerSecond      *float64 `hcl:"requests_per_second"`
+	MaxConcurrentRequests  *int     `hcl:"max_concurrent_requests"`
 }
 
 func ConfigInstance() interface{} {
@@ -1,9 +1,18 @@
 package stripe
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/stripe/stripe-go/v76"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 )
 
+// isNotFoundError reports whether a request failed because the object does
+// not exist. Tables keyed on a parent object, such as the line items of an
+// invoice, are listed in every connected account, and all but the account
+// owning the object return this error, so they return no rows for it.
 func isNotFoundError(err error) bool {
 	if stripeErr, ok := err.(*stripe.Error); ok {
 		switch stripeErr.Code {
@@ -13,3 +22,47 @@ func isNotFoundError(err error) bool {
 	}
 	return false
 }
+
+// isNoUpcomingInvoiceError reports whether a customer or subscription has no
+// upcoming invoice to preview.
+func isNoUpcomingInvoiceError(err error) bool {
+	if stripeErr, ok := err.(*stripe.Error); ok {
+		return stripeErr.Code == stripe.ErrorCodeInvoiceUpcomingNone
+	}
+	return false
+}
+
+// isPermissionError reports whether a request was rejected because the API
+// key is not permitted to make it, e.g. a restricted key without read access
+// to the resource.
+func isPermissionError(err error) bool {
+	if stripeErr, ok := err.(*stripe.Error); ok {
+		return stripeErr.HTTPStatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// shouldIgnorePermissionError ignores permission errors with a warning, so
+// tables the API key cannot read return no rows. Connections can set
+// ignore_permission_errors to false to fail instead.
+func shouldIgnorePermissionError(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData, err error) bool {
+	if !isPermissionError(err) {
+		return false
+	}
+	stripeConfig := GetConfig(d.Connection)
+	if stripeConfig.IgnorePermissionErrors != nil && !*stripeConfig.IgnorePermissionErrors {
+		return false
+	}
+	tableName := ""
+	if d.Table != nil {
+		tableName = d.Table.Name
+	}
+	plugin.Logger(ctx).Warn("stripe.shouldIgnorePermissionError", "table", tableName, "message", err.(*stripe.Error).Msg)
+	return true
+}
+
+// shouldIgnoreGetError ignores missing resources, and permission errors
+// unless the connection disables it.
+func shouldIgnoreGetError(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData, err error) bool {
+	return isNotFoundError(err) || shouldIgnorePermissionError(ctx, d, h, err)
+}
@@ -0,0 +1,275 @@
+package stripe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/form"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/connection"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/context_key"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+)
+
+// fakeRequest is a request received by the fake backend.
+type fakeRequest struct {
+	Method        string
+	Path          string
+	Query         url.Values
+	StripeAccount string
+}
+
+// fakeBackend is a stripe.Backend that records requests and serves canned
+// JSON responses instead of calling the Stripe API.
+type fakeBackend struct {
+	mu       sync.Mutex
+	requests []fakeRequest
+	// responses holds the bodies returned by successive requests to a path.
+	// The last body is repeated once the others have been served, and a path
+	// without responses returns a resource_missing error. Responses queued
+	// for a connected account are keyed on the account and path.
+	responses map[string][]string
+	// errors holds the errors returned by requests to a path.
+	errors map[string]*stripe.Error
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{responses: map[string][]string{}, errors: map[string]*stripe.Error{}}
+}
+
+// respondError makes requests to a path fail with an error.
+func (b *fakeBackend) respondError(path string, err *stripe.Error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.errors[path] = err
+}
+
+// respond queues the response bodies for a path.
+func (b *fakeBackend) respond(path string, bodies ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.responses[path] = append(b.responses[path], bodies...)
+}
+
+// respondForAccount queues the response bodies for a path when requested on
+// behalf of a connected account. Requests to the path for other accounts
+// return a resource_missing error, unless responses are queued for the path.
+func (b *fakeBackend) respondForAccount(account, path string, bodies ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := account + " " + path
+	b.responses[key] = append(b.responses[key], bodies...)
+}
+
+// requestsTo returns the requests received for a path.
+func (b *fakeBackend) requestsTo(path string) []fakeRequest {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var requests []fakeRequest
+	for _, r := range b.requests {
+		if r.Path == path {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+func (b *fakeBackend) Call(method, path, key string, params stripe.ParamsContainer, v stripe.LastResponseSetter) error {
+	body := &form.Values{}
+	var commonParams *stripe.Params
+	if params != nil {
+		form.AppendTo(body, params)
+		commonParams = params.GetParams()
+	}
+	return b.CallRaw(method, path, key, body, commonParams, v)
+}
+
+func (b *fakeBackend) CallStreaming(method, path, key string, params stripe.ParamsContainer, v stripe.StreamingLastResponseSetter) error {
+	return fmt.Errorf("fake backend: streaming is not supported")
+}
+
+func (b *fakeBackend) CallRaw(method, path, key string, body *form.Values, params *stripe.Params, v stripe.LastResponseSetter) error {
+	request := fakeRequest{Method: method, Path: path, Query: url.Values{}}
+	if body != nil {
+		request.Query = body.ToValues()
+	}
+	if params != nil && params.StripeAccount != nil {
+		request.StripeAccount = *params.StripeAccount
+	}
+
+	b.mu.Lock()
+	b.requests = append(b.requests, request)
+	responseKey := path
+	if _, ok := b.responses[request.StripeAccount+" "+path]; ok {
+		responseKey = request.StripeAccount + " " + path
+	}
+	bodies := b.responses[responseKey]
+	if len(bodies) > 1 {
+		b.responses[responseKey] = bodies[1:]
+	}
+	err := b.errors[path]
+	b.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	if len(bodies) == 0 {
+		return &stripe.Error{
+			Code:           stripe.ErrorCodeResourceMissing,
+			HTTPStatusCode: http.StatusNotFound,
+			Msg:            fmt.Sprintf("No such resource: '%s'", path),
+			Type:           stripe.ErrorTypeInvalidRequest,
+		}
+	}
+	if err := json.Unmarshal([]byte(bodies[0]), v); err != nil {
+		return err
+	}
+	v.SetLastResponse(&stripe.APIResponse{RawJSON: []byte(bodies[0]), StatusCode: http.StatusOK})
+	return nil
+}
+
+func (b *fakeBackend) CallMultipart(method, path, key, boundary string, body *bytes.Buffer, params *stripe.Params, v stripe.LastResponseSetter) error {
+	return fmt.Errorf("fake backend: multipart requests are not supported")
+}
+
+func (b *fakeBackend) SetMaxNetworkRetries(maxNetworkRetries int64) {}
+
+// permissionError returns the error Stripe returns when a restricted key
+// does not have a permission.
+func permissionError(permission string) *stripe.Error {
+	return &stripe.Error{
+		HTTPStatusCode: http.StatusForbidden,
+		Msg:            fmt.Sprintf("The provided key 'rk_test_***123' does not have the required permissions for this endpoint. Having the '%s' permission would allow this request to continue.", permission),
+		Type:           stripe.ErrorTypeInvalidRequest,
+	}
+}
+
+// listPage returns a list response containing objects with the given ids.
+func listPage(object string, hasMore bool, ids ...string) string {
+	return fmt.Sprintf(`{"object":"list","has_more":%t,"data":[%s]}`, hasMore, objects(object, ids...))
+}
+
+// searchPage returns a search response containing objects with the given
+// ids. A non-empty nextPage means more results are available.
+func searchPage(object string, nextPage string, ids ...string) string {
+	next := "null"
+	if nextPage != "" {
+		next = fmt.Sprintf("%q", nextPage)
+	}
+	return fmt.Sprintf(`{"object":"search_result","has_more":%t,"next_page":%s,"data":[%s]}`, nextPage != "", next, objects(object, ids...))
+}
+
+func objects(object string, ids ...string) string {
+	var items []string
+	for _, id := range ids {
+		items = append(items, fmt.Sprintf(`{"id":%q,"object":%q}`, id, object))
+	}
+	return strings.Join(items, ",")
+}
+
+// testContext returns a context with the logger that Steampipe provides to
+// hydrate functions.
+func testContext() context.Context {
+	return context.WithValue(context.Background(), context_key.Logger, hclog.NewNullLogger())
+}
+
+// testQueryData is a query against the fake backend.
+type testQueryData struct {
+	*plugin.QueryData
+	backend *fakeBackend
+	items   []interface{}
+}
+
+// newTestQueryData returns query data for a connection whose Stripe client
+// uses a fake backend. The quals are also set as the equals quals where they
+// are single "=" quals, as Steampipe does.
+func newTestQueryData(t *testing.T, limit *int64, qs ...*quals.Qual) *testQueryData {
+	t.Helper()
+
+	backend := newFakeBackend()
+	newBackendsOrig := newBackends
+	newBackends = func(stripeConfig, *requestLimiter) *stripe.Backends {
+		return &stripe.Backends{API: backend, Uploads: backend}
+	}
+	t.Cleanup(func() { newBackends = newBackendsOrig })
+
+	connectionCache, err := connection.NewConnectionCache(t.Name(), 1000)
+	if err != nil {
+		t.Fatalf("creating connection cache: %v", err)
+	}
+
+	qualMap := plugin.KeyColumnQualMap{}
+	for _, q := range qs {
+		if qualMap[q.Column] == nil {
+			qualMap[q.Column] = &plugin.KeyColumnQuals{Name: q.Column}
+		}
+		qualMap[q.Column].Quals = append(qualMap[q.Column].Quals, q)
+	}
+
+	td := &testQueryData{backend: backend}
+	td.QueryData = &plugin.QueryData{
+		Connection: &plugin.Connection{
+			Name:   t.Name(),
+			Config: stripeConfig{APIKey: stripe.String("sk_test_123")},
+		},
+		ConnectionCache:   connectionCache,
+		ConnectionManager: connection.NewManager(connectionCache),
+		Quals:             qualMap,
+		EqualsQuals:       qualMap.ToEqualsQualValueMap(),
+		QueryContext:      &plugin.QueryContext{Limit: limit},
+	}
+	td.StreamListItem = func(_ context.Context, items ...interface{}) {
+		td.items = append(td.items, items...)
+	}
+	return td
+}
+
+func stringQual(column, operator, value string) *quals.Qual {
+	return &quals.Qual{Column: column, Operator: operator, Value: &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: value}}}
+}
+
+func stringListQual(column string, values ...string) *quals.Qual {
+	list := &proto.QualValueList{}
+	for _, v := range values {
+		list.Values = append(list.Values, &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: v}})
+	}
+	return &quals.Qual{Column: column, Operator: "=", Value: &proto.QualValue{Value: &proto.QualValue_ListValue{ListValue: list}}}
+}
+
+func int64Qual(column, operator string, value int64) *quals.Qual {
+	return &quals.Qual{Column: column, Operator: operator, Value: &proto.QualValue{Value: &proto.QualValue_Int64Value{Int64Value: value}}}
+}
+
+func boolQual(column, operator string, value bool) *quals.Qual {
+	return &quals.Qual{Column: column, Operator: operator, Value: &proto.QualValue{Value: &proto.QualValue_BoolValue{BoolValue: value}}}
+}
+
+func jsonbQual(column, operator, value string) *quals.Qual {
+	return &quals.Qual{Column: column, Operator: operator, Value: &proto.QualValue{Value: &proto.QualValue_JsonbValue{JsonbValue: value}}}
+}
+
+func timestampQual(column, operator string, value int64) *quals.Qual {
+	return &quals.Qual{Column: column, Operator: operator, Value: &proto.QualValue{Value: &proto.QualValue_TimestampValue{TimestampValue: timestamppb.New(time.Unix(value, 0))}}}
+}
+
+func timestampListQual(column string, values ...int64) *quals.Qual {
+	list := &proto.QualValueList{}
+	for _, v := range values {
+		list.Values = append(list.Values, &proto.QualValue{Value: &proto.QualValue_TimestampValue{TimestampValue: timestamppb.New(time.Unix(v, 0))}})
+	}
+	return &quals.Qual{Column: column, Operator: "=", Value: &proto.QualValue{Value: &proto.QualValue_ListValue{ListValue: list}}}
+}
@@ -0,0 +1,146 @@
+package stripe
+
+import (
+	"context"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+const (
+	// defaultListSlices is the number of slices a created range is split into
+	// unless the connection sets list_slices.
+	defaultListSlices = 8
+	// defaultMaxConcurrentSlices is the number of slices listed at once unless
+	// the connection sets max_concurrent_slices.
+	defaultMaxConcurrentSlices = 4
+	// minSliceSeconds is the smallest slice a created range is split into.
+	// Each slice costs at least one request, so ranges shorter than two weeks
+	// are still listed with a single call, and only ranges large enough to
+	// hold many pages are sliced.
+	minSliceSeconds = 7 * 24 * 60 * 60
+)
+
+func listSlices(d *plugin.QueryData) int64 {
+	stripeConfig := GetConfig(d.Connection)
+	if stripeConfig.ListSlices != nil {
+		return int64(*stripeConfig.ListSlices)
+	}
+	return defaultListSlices
+}
+
+func maxConcurrentSlices(d *plugin.QueryData) int {
+	stripeConfig := GetConfig(d.Connection)
+	if stripeConfig.MaxConcurrentSlices != nil && *stripeConfig.MaxConcurrentSlices > 0 {
+		return *stripeConfig.MaxConcurrentSlices
+	}
+	return defaultMaxConcurrentSlices
+}
+
+// slices splits a bounded range into at most n slices of at least
+// minSliceSeconds, newest first to match the order lists are returned in.
+// Unbounded ranges are not split.
+func (r timeRange) slices(n int64) []timeRange {
+	if r.Start == nil || r.End == nil {
+		return []timeRange{r}
+	}
+	width := *r.End - *r.Start + 1
+	if n > width/minSliceSeconds {
+		n = width / minSliceSeconds
+	}
+	if n <= 1 {
+		return []timeRange{r}
+	}
+
+	size, remainder := width/n, width%n
+	slices := make([]timeRange, 0, n)
+	end := *r.End
+	for i := int64(0); i < n; i++ {
+		start := end - size + 1
+		if i < remainder {
+			start--
+		}
+		slices = append(slices, timeRange{Start: stripe.Int64(start), End: stripe.Int64(end)})
+		end = start - 1
+	}
+	return slices
+}
+
+// sliceCreatedRanges splits the created range of a list call into slices
+// that are listed concurrently. Ranges without a start or end are only split
+// if the connection sets slice_unbounded_lists, from the creation of the
+// account to now.
+func sliceCreatedRanges(ctx context.Context, d *plugin.QueryData, r queryRanges) []queryRanges {
+	n := listSlices(d)
+	if n <= 1 {
+		return []queryRanges{r}
+	}
+
+	stripeConfig := GetConfig(d.Connection)
+	created := r["created"]
+	if stripeConfig.SliceUnboundedLists != nil && *stripeConfig.SliceUnboundedLists && (created.Start == nil || created.End == nil) {
+		created = boundCreatedRange(ctx, d, created)
+	}
+
+	var sliced []queryRanges
+	for _, s := range created.slices(n) {
+		slice := queryRanges{"created": s}
+		for k, v := range r {
+			if k != "created" {
+				slice[k] = v
+			}
+		}
+		sliced = append(sliced, slice)
+	}
+	return sliced
+}
+
+// boundCreatedRange bounds a created range by the creation of the account
+// and now. Nothing can be created before the account, so this does not
+// change the objects the range holds.
+func boundCreatedRange(ctx context.Context, d *plugin.QueryData, r timeRange) timeRange {
+	if r.Start == nil {
+		created, err := getAccountCreated(ctx, d)
+		if err != nil || created == 0 {
+			plugin.Logger(ctx).Warn("stripe.boundCreatedRange", "error", err)
+			return r
+		}
+		r.Start = stripe.Int64(created)
+	}
+	if r.End == nil {
+		r.End = stripe.Int64(time.Now().Unix())
+	}
+	return r
+}
+
+// getAccountCreated returns the creation time of the account being queried.
+func getAccountCreated(ctx context.Context, d *plugin.QueryData) (int64, error) {
+	id := connectedAccount(ctx)
+	if id == nil {
+		acc, err := getAccountMemoized(ctx, d, nil)
+		if err != nil {
+			return 0, err
+		}
+		return acc.(*stripe.Account).Created, nil
+	}
+
+	cacheKey := "stripe_account_created_" + *id
+	if cachedData, ok := d.ConnectionManager.Cache.Get(cacheKey); ok {
+		return cachedData.(int64), nil
+	}
+
+	conn, err := connect(ctx, d)
+	if err != nil {
+		return 0, err
+	}
+	acc, err := conn.Accounts.GetByID(*id, &stripe.AccountParams{Params: stripe.Params{Context: ctx}})
+	if err != nil {
+		return 0, err
+	}
+
+	d.ConnectionManager.Cache.Set(cacheKey, acc.Created)
+
+	return acc.Created, nil
+}
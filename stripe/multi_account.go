@@ -0,0 +1,164 @@
+package stripe
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+const (
+	matrixKeyAccount   = "account_id"
+	matrixKeyConnected = "connected"
+	matrixKeyError     = "error"
+)
+
+// connectedAccountMatrix returns one matrix item per connected account
+// configured in connected_account_ids. Entries containing a wildcard (e.g.
+// "acct_*" or "*") are expanded against the connected accounts of the
+// platform. When no connected accounts are configured, tables are queried
+// once against the platform account.
+//
+// Steampipe skips the matrix items that do not match an account_id filter,
+// so in an aggregator connection only the connections holding the account
+// are queried.
+func connectedAccountMatrix(ctx context.Context, d *plugin.QueryData) []map[string]interface{} {
+	stripeConfig := GetConfig(d.Connection)
+	if len(stripeConfig.ConnectedAccountIds) == 0 {
+		return platformAccountMatrix(ctx, d)
+	}
+
+	ids, err := getConnectedAccountIds(ctx, d, stripeConfig.ConnectedAccountIds)
+	if err != nil {
+		// Return the error in a matrix item, so the hydrate functions fail with
+		// it instead of the query returning no rows or silently falling back to
+		// the platform account. It is wrapped so it is not ignored as a
+		// permission error of the table.
+		plugin.Logger(ctx).Error("connectedAccountMatrix", "connected_account_ids", stripeConfig.ConnectedAccountIds, "error", err)
+		return []map[string]interface{}{{matrixKeyError: fmt.Errorf("resolving connected_account_ids: %w", err)}}
+	}
+
+	matrix := make([]map[string]interface{}, len(ids))
+	for i, id := range ids {
+		matrix[i] = map[string]interface{}{matrixKeyAccount: id, matrixKeyConnected: true}
+	}
+	return matrix
+}
+
+// platformAccountMatrix returns a single matrix item for the platform
+// account when the query filters on account_id, so the filter can skip the
+// connection. The platform account ID is cached until the connection config
+// changes, so only the first query of a connection retrieves it. Otherwise,
+// or if the account cannot be retrieved, it returns nil to query the
+// platform account without a matrix.
+func platformAccountMatrix(ctx context.Context, d *plugin.QueryData) []map[string]interface{} {
+	if d.QueryContext == nil || d.QueryContext.UnsafeQuals[matrixKeyAccount] == nil {
+		return nil
+	}
+	id, err := getPlatformAccountId(ctx, d, nil)
+	if err != nil {
+		plugin.Logger(ctx).Warn("platformAccountMatrix", "error", err)
+		return nil
+	}
+	return []map[string]interface{}{{matrixKeyAccount: id, matrixKeyConnected: false}}
+}
+
+// getConnectedAccountIds resolves the configured account IDs and patterns to
+// a de-duplicated list of connected account IDs.
+func getConnectedAccountIds(ctx context.Context, d *plugin.QueryData, patterns []string) ([]string, error) {
+	var ids []string
+	seen := map[string]bool{}
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	var wildcards []string
+	for _, p := range patterns {
+		if strings.ContainsAny(p, "*?[") {
+			wildcards = append(wildcards, p)
+			continue
+		}
+		add(p)
+	}
+	if len(wildcards) == 0 {
+		return ids, nil
+	}
+
+	all, err := listConnectedAccountIds(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range all {
+		for _, p := range wildcards {
+			if ok, _ := path.Match(p, id); ok {
+				add(id)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
+// listConnectedAccountIds lists the IDs of all accounts connected to the
+// platform account.
+func listConnectedAccountIds(ctx context.Context, d *plugin.QueryData) ([]string, error) {
+	// Listing connected accounts is expensive for large platforms, so cache
+	// the result per connection
+	cacheKey := "stripe_connected_account_ids"
+	if cachedData, ok := d.ConnectionManager.Cache.Get(cacheKey); ok {
+		return cachedData.([]string), nil
+	}
+
+	conn, err := connect(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &stripe.AccountListParams{
+		ListParams: stripe.ListParams{
+			Context: ctx,
+			Limit:   stripe.Int64(100),
+		},
+	}
+
+	var ids []string
+	i := conn.Accounts.List(params)
+	for i.Next() {
+		ids = append(ids, i.Account().ID)
+	}
+	if err := i.Err(); err != nil {
+		return nil, err
+	}
+
+	d.ConnectionManager.Cache.Set(cacheKey, ids)
+
+	return ids, nil
+}
+
+// matrixError returns the error of the matrix item being queried, if the
+// accounts of the connection could not be resolved.
+func matrixError(ctx context.Context) error {
+	err, _ := plugin.GetMatrixItem(ctx)[matrixKeyError].(error)
+	return err
+}
+
+// connectedAccount returns the connected account for the matrix item being
+// queried, for use as the StripeAccount of list and get params. It returns
+// nil when querying the platform account.
+func connectedAccount(ctx context.Context) *string {
+	matrixItem := plugin.GetMatrixItem(ctx)
+	if connected, _ := matrixItem[matrixKeyConnected].(bool); !connected {
+		return nil
+	}
+	if id, ok := matrixItem[matrixKeyAccount].(string); ok && id != "" {
+		return stripe.String(id)
+	}
+	return nil
+}
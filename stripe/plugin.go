@@ -13,26 +13,60 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 		ConnectionConfigSchema: &plugin.ConnectionConfigSchema{
 			NewInstance: ConfigInstance,
 		},
+		// Steampipe prunes a connection unless its single key column value
+		// matches the filter, but a connection with connected_account_ids holds
+		// rows of several accounts. account_id cannot be a key column without
+		// dropping those connections, so connections are keyed on the account
+		// that owns the API key instead, and filters on account_id are applied
+		// to the account matrix of each connection.
 		ConnectionKeyColumns: []plugin.ConnectionKeyColumn{
 			{
-				Name:    "account_id",
-				Hydrate: getAccountId,
+				Name:    "platform_account_id",
+				Hydrate: getPlatformAccountId,
+			},
+			{
+				Name:    "livemode",
+				Hydrate: getLivemode,
 			},
 		},
 		DefaultTransform: transform.FromGo().NullIfZero(),
 		DefaultGetConfig: &plugin.GetConfig{
-			ShouldIgnoreError: isNotFoundError,
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreGetError,
+			},
+		},
+		DefaultIgnoreConfig: &plugin.IgnoreConfig{
+			ShouldIgnoreErrorFunc: shouldIgnorePermissionError,
 		},
 		TableMap: map[string]*plugin.Table{
-			"stripe_account":           tableStripeAccount(ctx),
-			"stripe_charge":            tableStripeCharge(ctx),
-			"stripe_coupon":            tableStripeCoupon(ctx),
-			"stripe_customer":          tableStripeCustomer(ctx),
-			"stripe_invoice":           tableStripeInvoice(ctx),
-			"stripe_plan":              tableStripePlan(ctx),
-			"stripe_product":           tableStripeProduct(ctx),
-			"stripe_subscription":      tableStripeSubscription(ctx),
-			"stripe_subscription_item": tableStripeSubscriptionItem(ctx),
+			"stripe_account":                    tableStripeAccount(ctx),
+			"stripe_balance_transaction":        tableStripeBalanceTransaction(ctx),
+			"stripe_charge":                     tableStripeCharge(ctx),
+			"stripe_checkout_session":           tableStripeCheckoutSession(ctx),
+			"stripe_checkout_session_line_item": tableStripeCheckoutSessionLineItem(ctx),
+			"stripe_coupon":                     tableStripeCoupon(ctx),
+			"stripe_credit_note":                tableStripeCreditNote(ctx),
+			"stripe_credit_note_line_item":      tableStripeCreditNoteLineItem(ctx),
+			"stripe_customer":                   tableStripeCustomer(ctx),
+			"stripe_dispute":                    tableStripeDispute(ctx),
+			"stripe_event":                      tableStripeEvent(ctx),
+			"stripe_invoice":                    tableStripeInvoice(ctx),
+			"stripe_invoice_item":               tableStripeInvoiceItem(ctx),
+			"stripe_invoice_line_item":          tableStripeInvoiceLineItem(ctx),
+			"stripe_invoice_upcoming":           tableStripeInvoiceUpcoming(ctx),
+			"stripe_invoice_upcoming_line_item": tableStripeInvoiceUpcomingLineItem(ctx),
+			"stripe_key_permission":             tableStripeKeyPermission(ctx),
+			"stripe_payment_intent":             tableStripePaymentIntent(ctx),
+			"stripe_payment_link":               tableStripePaymentLink(ctx),
+			"stripe_payout":                     tableStripePayout(ctx),
+			"stripe_plan":                       tableStripePlan(ctx),
+			"stripe_price":                      tableStripePrice(ctx),
+			"stripe_product":                    tableStripeProduct(ctx),
+			"stripe_promotion_code":             tableStripePromotionCode(ctx),
+			"stripe_refund":                     tableStripeRefund(ctx),
+			"stripe_subscription":               tableStripeSubscription(ctx),
+			"stripe_subscription_item":          tableStripeSubscriptionItem(ctx),
+			"stripe_webhook_endpoint":           tableStripeWebhookEndpoint(ctx),
 		},
 	}
 	return p
@@ -0,0 +1,190 @@
+package stripe
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// timeRange is a range of Unix timestamps in seconds. Both bounds are
+// inclusive, and a nil bound is unbounded.
+type timeRange struct {
+	Start *int64
+	End   *int64
+}
+
+// listParams returns the List API params for the range, i.e. the exact value
+// of a range of a single second, or the bounds of the range. Both are nil for
+// an unbounded range.
+func (r timeRange) listParams() (*int64, *stripe.RangeQueryParams) {
+	if r.Start != nil && r.End != nil && *r.Start == *r.End {
+		return stripe.Int64(*r.Start), nil
+	}
+	if r.Start == nil && r.End == nil {
+		return nil, nil
+	}
+	rangeParams := &stripe.RangeQueryParams{}
+	if r.Start != nil {
+		rangeParams.GreaterThanOrEqual = *r.Start
+	}
+	if r.End != nil {
+		rangeParams.LesserThanOrEqual = *r.End
+	}
+	return nil, rangeParams
+}
+
+// addFilters adds the range to the filters of List API params, for ranges
+// the params do not model.
+func (r timeRange) addFilters(filters *stripe.Filters, key string) {
+	exact, rangeParams := r.listParams()
+	switch {
+	case exact != nil:
+		filters.AddFilter(key, "", strconv.FormatInt(*exact, 10))
+	case rangeParams != nil:
+		if r.Start != nil {
+			filters.AddFilter(key, "gte", strconv.FormatInt(*r.Start, 10))
+		}
+		if r.End != nil {
+			filters.AddFilter(key, "lte", strconv.FormatInt(*r.End, 10))
+		}
+	}
+}
+
+// timeRangeFromQuals translates the quals on a timestamp column into the
+// range to list. Bounds are intersected, and ok is false if they contradict
+// each other. IN lists are not translated: Steampipe lists each value of an
+// IN list on a single column separately, and lists with several of them are
+// left to Postgres to filter. Without quals the range is unbounded.
+func timeRangeFromQuals(d *plugin.QueryData, column string) (r timeRange, ok bool) {
+	if d.Quals[column] == nil {
+		return r, true
+	}
+
+	raise := func(v int64) {
+		if r.Start == nil || v > *r.Start {
+			r.Start = stripe.Int64(v)
+		}
+	}
+	lower := func(v int64) {
+		if r.End == nil || v < *r.End {
+			r.End = stripe.Int64(v)
+		}
+	}
+
+	for _, q := range d.Quals[column].Quals {
+		ts := q.Value.GetTimestampValue()
+		if ts == nil {
+			continue
+		}
+		switch q.Operator {
+		case "=":
+			// Stripe timestamps are whole seconds, so they never equal a
+			// timestamp with a fraction of a second
+			if ts.GetNanos() != 0 {
+				return timeRange{}, false
+			}
+			raise(ts.GetSeconds())
+			lower(ts.GetSeconds())
+		case ">":
+			raise(ts.GetSeconds() + 1)
+		case ">=":
+			raise(ceilSeconds(ts))
+		case "<":
+			lower(ceilSeconds(ts) - 1)
+		case "<=":
+			lower(ts.GetSeconds())
+		}
+	}
+
+	if r.Start != nil && r.End != nil && *r.Start > *r.End {
+		return timeRange{}, false
+	}
+	return r, true
+}
+
+// ceilSeconds rounds a timestamp up to whole seconds.
+func ceilSeconds(ts *timestamppb.Timestamp) int64 {
+	if ts.GetNanos() > 0 {
+		return ts.GetSeconds() + 1
+	}
+	return ts.GetSeconds()
+}
+
+// queryRanges holds the range of each timestamp column for a list call.
+type queryRanges map[string]timeRange
+
+// queryRangesFromQuals returns the ranges of the given timestamp columns to
+// make a list call for, or false if the quals of a column contradict each
+// other.
+func queryRangesFromQuals(d *plugin.QueryData, columns ...string) (queryRanges, bool) {
+	ranges := queryRanges{}
+	for _, column := range columns {
+		r, ok := timeRangeFromQuals(d, column)
+		if !ok {
+			return nil, false
+		}
+		ranges[column] = r
+	}
+	return ranges, true
+}
+
+// listRangeFunc lists the objects in the given ranges, passing each object to
+// stream until it returns false. It may be called concurrently, so it must
+// not modify state shared between calls.
+type listRangeFunc func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error
+
+// listQueryRanges translates the quals on the given timestamp columns into
+// ranges, and calls list for them. Without a query limit, the created range
+// is split into slices, and the calls are made concurrently with their rows
+// streamed as they arrive. Otherwise list is called once, and the rows
+// streamed stop at the limit.
+func listQueryRanges(ctx context.Context, d *plugin.QueryData, columns []string, list listRangeFunc) error {
+	var mu sync.Mutex
+	var count int64
+	limit := d.QueryContext.Limit
+	done := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return limit != nil && count >= *limit
+	}
+	stream := func(item interface{}) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if limit != nil && count >= *limit {
+			return false
+		}
+		d.StreamListItem(ctx, item)
+		count++
+		return limit == nil || count < *limit
+	}
+
+	r, ok := queryRangesFromQuals(d, columns...)
+	if !ok {
+		return nil
+	}
+	ranges := []queryRanges{r}
+	concurrency := 1
+	if limit == nil && slices.Contains(columns, "created") {
+		ranges = sliceCreatedRanges(ctx, d, r)
+		concurrency = maxConcurrentSlices(d)
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for _, r := range ranges {
+		r := r
+		g.Go(func() error {
+			if done() {
+				return nil
+			}
+			return list(gctx, r, stream)
+		})
+	}
+	return g.Wait()
+}
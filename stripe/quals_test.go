@@ -0,0 +1,232 @@
+package stripe
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+)
+
+func TestTimeRangeFromQuals(t *testing.T) {
+	fraction := &quals.Qual{Column: "created", Operator: "<", Value: &proto.QualValue{Value: &proto.QualValue_TimestampValue{TimestampValue: timestamppb.New(time.Unix(testEnd, 500000000))}}}
+
+	tests := []struct {
+		name   string
+		quals  []*quals.Qual
+		want   timeRange
+		wantOk bool
+	}{
+		{
+			name:   "no quals",
+			want:   timeRange{},
+			wantOk: true,
+		},
+		{
+			name:   "exclusive bounds",
+			quals:  []*quals.Qual{timestampQual("created", ">", testStart), timestampQual("created", "<", testEnd)},
+			want:   timeRange{Start: stripe.Int64(testStart + 1), End: stripe.Int64(testEnd - 1)},
+			wantOk: true,
+		},
+		{
+			name:   "fraction of a second",
+			quals:  []*quals.Qual{fraction},
+			want:   timeRange{End: stripe.Int64(testEnd)},
+			wantOk: true,
+		},
+		{
+			name: "intersected bounds",
+			quals: []*quals.Qual{
+				timestampQual("created", ">=", testStart),
+				timestampQual("created", ">", testStart+10),
+				timestampQual("created", "<=", testEnd),
+				timestampQual("created", "<", testEnd-10),
+			},
+			want:   timeRange{Start: stripe.Int64(testStart + 11), End: stripe.Int64(testEnd - 11)},
+			wantOk: true,
+		},
+		{
+			name:  "contradictory bounds",
+			quals: []*quals.Qual{timestampQual("created", ">", testEnd), timestampQual("created", "<", testStart)},
+			want:  timeRange{},
+		},
+		{
+			name:   "equal",
+			quals:  []*quals.Qual{timestampQual("created", "=", testStart)},
+			want:   timeRange{Start: stripe.Int64(testStart), End: stripe.Int64(testStart)},
+			wantOk: true,
+		},
+		{
+			name:   "equal within bounds",
+			quals:  []*quals.Qual{timestampQual("created", "=", testStart), timestampQual("created", "<=", testEnd)},
+			want:   timeRange{Start: stripe.Int64(testStart), End: stripe.Int64(testStart)},
+			wantOk: true,
+		},
+		{
+			name:  "equal outside bounds",
+			quals: []*quals.Qual{timestampQual("created", "=", testEnd+10), timestampQual("created", "<=", testEnd)},
+			want:  timeRange{},
+		},
+		{
+			// Steampipe only passes IN lists through when several columns have
+			// them, and Postgres filters the rows
+			name:   "in list is not translated",
+			quals:  []*quals.Qual{timestampListQual("created", testStart, testEnd), timestampQual("created", "<=", testEnd)},
+			want:   timeRange{End: stripe.Int64(testEnd)},
+			wantOk: true,
+		},
+		{
+			name:  "contradictory values",
+			quals: []*quals.Qual{timestampQual("created", "=", testStart), timestampQual("created", "=", testEnd)},
+			want:  timeRange{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestQueryData(t, nil, tt.quals...)
+			got, ok := timeRangeFromQuals(d.QueryData, "created")
+			if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got range %v, %t, want %v, %t", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestListQueryRanges(t *testing.T) {
+	const day = 24 * 60 * 60
+	const week = 7 * day
+
+	tests := []struct {
+		name      string
+		config    stripeConfig
+		quals     []*quals.Qual
+		limit     *int64
+		wantQuery []string
+		wantIds   []string
+	}{
+		{
+			name:      "unbounded range",
+			wantQuery: []string{"limit=100"},
+			wantIds:   []string{"ch_1"},
+		},
+		{
+			name:    "contradictory ranges",
+			quals:   []*quals.Qual{timestampQual("created", ">", testEnd), timestampQual("created", "<", testStart)},
+			wantIds: nil,
+		},
+		{
+			name:  "range sliced by week",
+			quals: []*quals.Qual{timestampQual("created", ">=", testStart), timestampQual("created", "<", testStart+3*week)},
+			wantQuery: []string{
+				"created%5Bgte%5D=1700000000&created%5Blte%5D=1700604799&limit=100",
+				"created%5Bgte%5D=1700604800&created%5Blte%5D=1701209599&limit=100",
+				"created%5Bgte%5D=1701209600&created%5Blte%5D=1701814399&limit=100",
+			},
+			wantIds: []string{"ch_1", "ch_2", "ch_3"},
+		},
+		{
+			name:   "range sliced into list_slices",
+			config: stripeConfig{ListSlices: intPtr(2)},
+			quals:  []*quals.Qual{timestampQual("created", ">=", testStart), timestampQual("created", "<=", testStart+3*week)},
+			wantQuery: []string{
+				"created%5Bgte%5D=1700000000&created%5Blte%5D=1700907199&limit=100",
+				"created%5Bgte%5D=1700907200&created%5Blte%5D=1701814400&limit=100",
+			},
+			wantIds: []string{"ch_1", "ch_2"},
+		},
+		{
+			name:  "short range not sliced",
+			quals: []*quals.Qual{timestampQual("created", ">=", testStart), timestampQual("created", "<=", testStart+13*day)},
+			wantQuery: []string{
+				"created%5Bgte%5D=1700000000&created%5Blte%5D=1701123200&limit=100",
+			},
+			wantIds: []string{"ch_1"},
+		},
+		{
+			name:   "slicing disabled",
+			config: stripeConfig{ListSlices: intPtr(1)},
+			quals:  []*quals.Qual{timestampQual("created", ">=", testStart), timestampQual("created", "<=", testStart+3*week)},
+			wantQuery: []string{
+				"created%5Bgte%5D=1700000000&created%5Blte%5D=1701814400&limit=100",
+			},
+			wantIds: []string{"ch_1"},
+		},
+		{
+			name:  "limit disables slicing",
+			quals: []*quals.Qual{timestampQual("created", ">=", testStart), timestampQual("created", "<=", testStart+3*week)},
+			limit: stripe.Int64(5),
+			wantQuery: []string{
+				"created%5Bgte%5D=1700000000&created%5Blte%5D=1701814400&limit=5",
+			},
+			wantIds: []string{"ch_1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestQueryData(t, tt.limit, tt.quals...)
+			tt.config.APIKey = stripe.String("sk_test_123")
+			d.Connection.Config = tt.config
+			d.backend.respond("/v1/charges",
+				listPage("charge", false, "ch_1"),
+				listPage("charge", false, "ch_2"),
+				listPage("charge", false, "ch_3"),
+			)
+
+			if _, err := listCharges(testContext(), d.QueryData, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Ranges are listed concurrently, so compare them in any order
+			var got []string
+			for _, r := range d.backend.requestsTo("/v1/charges") {
+				got = append(got, r.Query.Encode())
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.wantQuery) {
+				t.Errorf("got requests %v, want %v", got, tt.wantQuery)
+			}
+			sort.Slice(d.items, func(i, j int) bool {
+				return d.items[i].(*stripe.Charge).ID < d.items[j].(*stripe.Charge).ID
+			})
+			assertItemIds(t, d.items, tt.wantIds...)
+		})
+	}
+}
+
+func TestSliceUnboundedLists(t *testing.T) {
+	created := time.Now().Unix() - 2*7*24*60*60 - 60
+	d := newTestQueryData(t, nil)
+	d.Connection.Config = stripeConfig{APIKey: stripe.String("sk_test_123"), SliceUnboundedLists: stripe.Bool(true)}
+	d.backend.respond("/v1/account", fmt.Sprintf(`{"id":"acct_1","object":"account","created":%d}`, created))
+	d.backend.respond("/v1/charges", listPage("charge", false))
+
+	if _, err := listCharges(testContext(), d.QueryData, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	requests := d.backend.requestsTo("/v1/charges")
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2 slices", len(requests))
+	}
+	var starts []string
+	for _, r := range requests {
+		starts = append(starts, r.Query.Get("created[gte]"))
+	}
+	if !slices.Contains(starts, strconv.FormatInt(created, 10)) {
+		t.Errorf("got slices starting at %v, want a slice starting at the account creation %d", starts, created)
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
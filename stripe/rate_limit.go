@@ -0,0 +1,140 @@
+package stripe
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+const (
+	// defaultMaxRetries is the number of times stripe-go retries a failed
+	// request unless the connection sets max_retries.
+	defaultMaxRetries = 10
+	// Stripe allows 100 read requests per second in live mode and 25 in test
+	// mode. The defaults leave headroom for other clients of the account.
+	defaultLiveRequestsPerSecond = 80
+	defaultTestRequestsPerSecond = 20
+	// defaultMaxConcurrentRequests is the number of requests in flight at once
+	// unless the connection sets max_concurrent_requests.
+	defaultMaxConcurrentRequests = 20
+)
+
+func maxRetries(stripeConfig stripeConfig) int64 {
+	if stripeConfig.MaxRetries != nil && *stripeConfig.MaxRetries >= 0 {
+		return int64(*stripeConfig.MaxRetries)
+	}
+	return defaultMaxRetries
+}
+
+// requestLimiter throttles the requests of a connection with a token bucket,
+// and caps the requests in flight.
+type requestLimiter struct {
+	limiter     *rate.Limiter
+	concurrency *semaphore.Weighted
+}
+
+func newRequestLimiter(requestsPerSecond float64, maxConcurrentRequests int64) *requestLimiter {
+	burst := int(requestsPerSecond)
+	if burst < 1 {
+		burst = 1
+	}
+	return &requestLimiter{
+		limiter:     rate.NewLimiter(rate.Limit(requestsPerSecond), burst),
+		concurrency: semaphore.NewWeighted(maxConcurrentRequests),
+	}
+}
+
+// wait blocks until a request can be made, and returns the function to call
+// once it is done.
+func (l *requestLimiter) wait(ctx context.Context) (func(), error) {
+	if err := l.concurrency.Acquire(ctx, 1); err != nil {
+		return nil, err
+	}
+	if err := l.limiter.Wait(ctx); err != nil {
+		l.concurrency.Release(1)
+		return nil, err
+	}
+	return func() { l.concurrency.Release(1) }, nil
+}
+
+// requestLimiters holds the limiter of each connection. They outlive the
+// Stripe clients in the connection cache, so the budget is shared by all
+// tables and hydrate functions of the connection.
+var requestLimiters = struct {
+	sync.Mutex
+	m map[string]connectionLimiter
+}{m: map[string]connectionLimiter{}}
+
+// connectionLimiter is the limiter of a connection along with the settings it
+// was created with.
+type connectionLimiter struct {
+	settings requestLimiterSettings
+	limiter  *requestLimiter
+}
+
+type requestLimiterSettings struct {
+	live                  bool
+	requestsPerSecond     float64
+	maxConcurrentRequests int64
+}
+
+// getRequestLimiter returns the limiter of the connection. Live and test
+// mode have separate rate limits in Stripe, and a connection only has a key
+// for one of them, so each connection has its own budget. Changing the
+// settings of a connection replaces its limiter.
+func getRequestLimiter(d *plugin.QueryData) *requestLimiter {
+	stripeConfig := GetConfig(d.Connection)
+
+	settings := requestLimiterSettings{
+		live:                  isLivemode(d),
+		requestsPerSecond:     defaultTestRequestsPerSecond,
+		maxConcurrentRequests: defaultMaxConcurrentRequests,
+	}
+	if settings.live {
+		settings.requestsPerSecond = defaultLiveRequestsPerSecond
+	}
+	if stripeConfig.RequestsPerSecond != nil && *stripeConfig.RequestsPerSecond > 0 {
+		settings.requestsPerSecond = *stripeConfig.RequestsPerSecond
+	}
+	if stripeConfig.MaxConcurrentRequests != nil && *stripeConfig.MaxConcurrentRequests > 0 {
+		settings.maxConcurrentRequests = int64(*stripeConfig.MaxConcurrentRequests)
+	}
+
+	connectionName := ""
+	if d.Connection != nil {
+		connectionName = d.Connection.Name
+	}
+
+	requestLimiters.Lock()
+	defer requestLimiters.Unlock()
+	c, ok := requestLimiters.m[connectionName]
+	if !ok || c.settings != settings {
+		c = connectionLimiter{
+			settings: settings,
+			limiter:  newRequestLimiter(settings.requestsPerSecond, settings.maxConcurrentRequests),
+		}
+		requestLimiters.m[connectionName] = c
+	}
+	return c.limiter
+}
+
+// rateLimitTransport waits for the limiter before each request, including
+// the retries stripe-go makes.
+type rateLimitTransport struct {
+	limiter *requestLimiter
+	base    http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	done, err := t.limiter.wait(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,142 @@
+package stripe
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+func TestRateLimitTransportConcurrency(t *testing.T) {
+	const limit = 2
+	var inFlight, maxInFlight int32
+	entered := make(chan struct{}, 6)
+	release := make(chan struct{})
+	transport := &rateLimitTransport{
+		limiter: newRequestLimiter(1000, limit),
+		base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				m := atomic.LoadInt32(&maxInFlight)
+				if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+					break
+				}
+			}
+			entered <- struct{}{}
+			<-release
+			atomic.AddInt32(&inFlight, -1)
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, "https://api.stripe.com/v1/charges", nil)
+			if _, err := transport.RoundTrip(req); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+
+	// Requests are held until released, so the limit is reached before any
+	// request completes
+	for i := 0; i < limit; i++ {
+		<-entered
+	}
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&maxInFlight); got > limit {
+		t.Errorf("got %d requests in flight, want at most %d", got, limit)
+	}
+}
+
+func TestRateLimitTransportRate(t *testing.T) {
+	transport := &rateLimitTransport{
+		limiter: newRequestLimiter(50, 100),
+		base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+	}
+
+	// The first 50 requests use the burst, and the next 10 wait 20ms each
+	start := time.Now()
+	for i := 0; i < 60; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.stripe.com/v1/charges", nil)
+		if _, err := transport.RoundTrip(req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("made 60 requests in %v, want at least 150ms at 50 requests per second", elapsed)
+	}
+}
+
+func TestGetRequestLimiter(t *testing.T) {
+	queryData := func(name string, config stripeConfig) *plugin.QueryData {
+		return &plugin.QueryData{Connection: &plugin.Connection{Name: name, Config: config}}
+	}
+	test := stripeConfig{APIKey: stripe.String("sk_test_123")}
+	live := stripeConfig{APIKey: stripe.String("sk_live_123")}
+
+	shared := getRequestLimiter(queryData(t.Name(), test))
+	if getRequestLimiter(queryData(t.Name(), test)) != shared {
+		t.Errorf("got a new limiter for the same connection, want the shared one")
+	}
+	if getRequestLimiter(queryData(t.Name()+"_other", test)) == shared {
+		t.Errorf("got the limiter of another connection, want a separate one")
+	}
+	if getRequestLimiter(queryData(t.Name()+"_live", live)) == shared {
+		t.Errorf("got the test mode limiter for a live mode key, want a separate one")
+	}
+
+	if got := shared.limiter.Limit(); got != defaultTestRequestsPerSecond {
+		t.Errorf("got test mode limit %v, want %v", got, defaultTestRequestsPerSecond)
+	}
+	if got := getRequestLimiter(queryData(t.Name()+"_live", live)).limiter.Limit(); got != defaultLiveRequestsPerSecond {
+		t.Errorf("got live mode limit %v, want %v", got, defaultLiveRequestsPerSecond)
+	}
+
+	// Changing the settings of a connection replaces its limiter, rather than
+	// adding another one
+	configured := stripeConfig{APIKey: stripe.String("sk_test_123"), RequestsPerSecond: stripe.Float64(5)}
+	replaced := getRequestLimiter(queryData(t.Name(), configured))
+	if got := replaced.limiter.Limit(); got != 5 {
+		t.Errorf("got configured limit %v, want 5", got)
+	}
+	requestLimiters.Lock()
+	current := requestLimiters.m[t.Name()].limiter
+	requestLimiters.Unlock()
+	if current != replaced {
+		t.Errorf("got limiter %p for the connection, want the replacement %p", current, replaced)
+	}
+}
+
+func TestMaxRetries(t *testing.T) {
+	tests := []struct {
+		name   string
+		config stripeConfig
+		want   int64
+	}{
+		{name: "default", want: defaultMaxRetries},
+		{name: "configured", config: stripeConfig{MaxRetries: intPtr(2)}, want: 2},
+		{name: "disabled", config: stripeConfig{MaxRetries: intPtr(0)}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backends := newBackends(tt.config, newRequestLimiter(1, 1))
+			if got := backends.API.(*stripe.BackendImplementation).MaxNetworkRetries; got != tt.want {
+				t.Errorf("got %d max retries, want %d", got, tt.want)
+			}
+		})
+	}
+}
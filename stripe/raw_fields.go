@@ -0,0 +1,80 @@
+package stripe
+
+import (
+	"encoding/json"
+
+	"github.com/stripe/stripe-go/v76"
+)
+
+// decodeRawFields decodes the object in a response into T, for the fields
+// the stripe-go structs do not decode. These are fields that moved in later
+// API versions, or that were added after the version stripe-go is pinned to.
+func decodeRawFields[T any](response *stripe.APIResponse) T {
+	var f T
+	if response != nil {
+		_ = json.Unmarshal(response.RawJSON, &f)
+	}
+	return f
+}
+
+// rawPage decodes the objects in a page of list or search results into T,
+// once per page.
+type rawPage[T any] struct {
+	response *stripe.APIResponse
+	fields   map[string]T
+}
+
+// get returns the fields of an object in the page the response belongs to.
+func (p *rawPage[T]) get(response *stripe.APIResponse, id string) T {
+	if response == nil {
+		var f T
+		return f
+	}
+	if response != p.response {
+		var ids struct {
+			Data []struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		var page struct {
+			Data []T `json:"data"`
+		}
+		_ = json.Unmarshal(response.RawJSON, &ids)
+		_ = json.Unmarshal(response.RawJSON, &page)
+		p.response = response
+		p.fields = map[string]T{}
+		for n, f := range page.Data {
+			if n < len(ids.Data) {
+				p.fields[ids.Data[n].ID] = f
+			}
+		}
+	}
+	return p.fields[id]
+}
+
+// rawValue returns a raw JSON field, or nil if it is missing or null.
+func rawValue(raw json.RawMessage) interface{} {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	return raw
+}
+
+// expandableId returns the ID of a field that is either an ID or an expanded
+// object.
+func expandableId(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil {
+		return id
+	}
+	var object struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &object); err == nil {
+		return object.ID
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+package stripe
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+)
+
+type searchFieldType int
+
+const (
+	searchFieldString searchFieldType = iota
+	searchFieldNumber
+	searchFieldTimestamp
+	searchFieldBool
+	searchFieldMetadata
+)
+
+// searchField maps a column to a field of the Stripe Search query language.
+// See https://stripe.com/docs/search#search-query-language
+type searchField struct {
+	Column string
+	Field  string
+	Type   searchFieldType
+	// ListFilter is set when the List endpoint can also filter on the column
+	// by equality or range, so such a qual alone is not a reason to use the
+	// Search API
+	ListFilter bool
+}
+
+// buildSearchQuery translates the query quals into a Stripe Search query.
+// It returns an empty string when none of the quals require the Search API,
+// i.e. when the List endpoint can filter on all of them, or when a column
+// only the List endpoint can filter on is constrained.
+func buildSearchQuery(d *plugin.QueryData, fields []searchField, listOnlyColumns ...string) string {
+	for _, c := range listOnlyColumns {
+		if d.Quals[c] != nil {
+			return ""
+		}
+	}
+
+	var clauses []string
+	needsSearch := false
+	for _, f := range fields {
+		if d.Quals[f.Column] == nil {
+			continue
+		}
+		for _, q := range d.Quals[f.Column].Quals {
+			clause := searchClause(f, q.Operator, q.Value)
+			if clause == "" {
+				continue
+			}
+			clauses = append(clauses, clause)
+			if !f.ListFilter || q.Operator == "<>" || q.Operator == quals.QualOperatorLike {
+				needsSearch = true
+			}
+		}
+	}
+
+	if !needsSearch {
+		return ""
+	}
+	return strings.Join(clauses, " AND ")
+}
+
+// searchClause returns the search query clause for a single qual, or an
+// empty string if it cannot be expressed in the search query language.
+func searchClause(f searchField, operator string, value *proto.QualValue) string {
+	// The query language cannot combine AND with OR, so IN lists are left
+	// for Steampipe to filter
+	if value.GetListValue() != nil {
+		return ""
+	}
+
+	switch f.Type {
+	case searchFieldMetadata:
+		if operator != quals.QualOperatorJsonbContainsLeftRight {
+			return ""
+		}
+		var metadata map[string]interface{}
+		if err := json.Unmarshal([]byte(value.GetJsonbValue()), &metadata); err != nil {
+			return ""
+		}
+		var clauses []string
+		for k, v := range metadata {
+			s, ok := v.(string)
+			if !ok {
+				return ""
+			}
+			clauses = append(clauses, fmt.Sprintf("%s[%s]:%s", f.Field, quoteSearchValue(k), quoteSearchValue(s)))
+		}
+		return strings.Join(clauses, " AND ")
+
+	case searchFieldTimestamp:
+		// Stripe timestamps are whole seconds, so fractional timestamps are
+		// rounded the same way as in timeRangeFromQuals
+		ts := value.GetTimestampValue()
+		fractional := ts.GetNanos() > 0
+		switch operator {
+		case "=":
+			if !fractional {
+				return f.Field + ":" + strconv.FormatInt(ts.GetSeconds(), 10)
+			}
+		case "<>":
+			if !fractional {
+				return "-" + f.Field + ":" + strconv.FormatInt(ts.GetSeconds(), 10)
+			}
+		case ">":
+			return f.Field + ">" + strconv.FormatInt(ts.GetSeconds(), 10)
+		case ">=":
+			return f.Field + ">=" + strconv.FormatInt(ceilSeconds(ts), 10)
+		case "<":
+			return f.Field + "<=" + strconv.FormatInt(ceilSeconds(ts)-1, 10)
+		case "<=":
+			return f.Field + "<=" + strconv.FormatInt(ts.GetSeconds(), 10)
+		}
+
+	case searchFieldNumber:
+		v := strconv.FormatInt(value.GetInt64Value(), 10)
+		switch operator {
+		case "=":
+			return f.Field + ":" + v
+		case "<>":
+			return "-" + f.Field + ":" + v
+		case ">", ">=", "<", "<=":
+			return f.Field + operator + v
+		}
+
+	case searchFieldBool:
+		v := quoteSearchValue(strconv.FormatBool(value.GetBoolValue()))
+		switch operator {
+		case "=":
+			return f.Field + ":" + v
+		case "<>":
+			return "-" + f.Field + ":" + v
+		}
+
+	case searchFieldString:
+		switch operator {
+		case "=":
+			return f.Field + ":" + quoteSearchValue(value.GetStringValue())
+		case "<>":
+			return "-" + f.Field + ":" + quoteSearchValue(value.GetStringValue())
+		case quals.QualOperatorLike:
+			// Only patterns that reduce to a single substring can be pushed
+			// down, and Stripe requires at least 3 characters
+			s := strings.Trim(value.GetStringValue(), "%")
+			if len(s) < 3 || strings.ContainsAny(s, "%_") {
+				return ""
+			}
+			return f.Field + "~" + quoteSearchValue(s)
+		}
+	}
+
+	return ""
+}
+
+// quoteSearchValue quotes a string value for use in a search query.
+func quoteSearchValue(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
+}
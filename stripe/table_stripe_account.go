@@ -3,6 +3,7 @@ package stripe
 import (
 	"context"
 
+	"github.com/stripe/stripe-go/v76"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
@@ -15,6 +16,7 @@ func tableStripeAccount(ctx context.Context) *plugin.Table {
 		List: &plugin.ListConfig{
 			Hydrate: listAccount,
 		},
+		GetMatrixItemFunc: connectedAccountMatrix,
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
 			{Name: "id", Type: proto.ColumnType_STRING, Description: "Unique identifier for the account."},
@@ -49,7 +51,14 @@ func listAccount(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 		return nil, err
 	}
 
-	item, err := conn.Accounts.Get()
+	// When querying a connected account, retrieve that account rather than
+	// the platform account that owns the API key
+	var item *stripe.Account
+	if id := connectedAccount(ctx); id != nil {
+		item, err = conn.Accounts.GetByID(*id, &stripe.AccountParams{Params: stripe.Params{Context: ctx}})
+	} else {
+		item, err = conn.Accounts.Get()
+	}
 	if err != nil {
 		plugin.Logger(ctx).Error("stripe_account.listAccount", "query_error", err)
 		return nil, err
@@ -0,0 +1,202 @@
+package stripe
+
+import (
+	"context"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripeBalanceTransaction(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_balance_transaction",
+		Description: "Balance transactions represent funds moving through your Stripe account.",
+		List: &plugin.ListConfig{
+			Hydrate: listBalanceTransactions,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "created", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
+				{Name: "currency", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "payout", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "source", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "type", Require: plugin.Optional, Operators: []string{"="}},
+			},
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getBalanceTransaction,
+			KeyColumns: plugin.SingleColumn("id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			// Basic fields
+			{
+				Name:        "id",
+				Description: "Unique identifier for the balance transaction.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "amount",
+				Description: "Gross amount of the transaction, in the smallest currency unit.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "available_on",
+				Description: "Date the transaction's net funds will become available in the Stripe balance.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("AvailableOn").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "created",
+				Description: "Timestamp when the balance transaction was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Created").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "currency",
+				Description: "Currency of the balance transaction.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "description",
+				Description: "An arbitrary string attached to the object. Often useful for displaying to users.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "exchange_rate",
+				Description: "The exchange rate used, if applicable, for this transaction.",
+				Type:        proto.ColumnType_DOUBLE,
+			},
+			{
+				Name:        "fee",
+				Description: "Fees paid for this transaction, in the smallest currency unit.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "net",
+				Description: "Net amount of the transaction, in the smallest currency unit.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "payout",
+				Description: "ID of the payout the balance transaction was settled in. Only populated when the query specifies a payout.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("payout"),
+			},
+			{
+				Name:        "reporting_category",
+				Description: "Learn more about how reporting categories can help you understand balance transactions from an accounting perspective.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "source",
+				Description: "ID of the Stripe object to which this transaction is related.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.ID"),
+			},
+			{
+				Name:        "source_type",
+				Description: "Type of the Stripe object to which this transaction is related (e.g., charge, refund, payout).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.Type"),
+			},
+			{
+				Name:        "status",
+				Description: "If the transaction's net funds are available in the Stripe balance yet. Either available or pending.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "type",
+				Description: "Transaction type (e.g., charge, refund, adjustment, payout, stripe_fee).",
+				Type:        proto.ColumnType_STRING,
+			},
+
+			// JSON columns for complex data
+			{
+				Name:        "fee_details",
+				Description: "Detailed breakdown of fees paid for this transaction.",
+				Type:        proto.ColumnType_JSON,
+			},
+		}),
+	}
+}
+
+func listBalanceTransactions(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_balance_transaction.listBalanceTransactions", "connection_error", err)
+		return nil, err
+	}
+	params := &stripe.BalanceTransactionListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	q := d.EqualsQuals
+	if q["currency"] != nil {
+		params.Currency = stripe.String(q["currency"].GetStringValue())
+	}
+	if q["payout"] != nil {
+		params.Payout = stripe.String(q["payout"].GetStringValue())
+	}
+	if q["source"] != nil {
+		params.Source = stripe.String(q["source"].GetStringValue())
+	}
+	if q["type"] != nil {
+		params.Type = stripe.String(q["type"].GetStringValue())
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	err = listQueryRanges(ctx, d, []string{"created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.Created, params.CreatedRange = r["created"].listParams()
+		i := conn.BalanceTransactions.List(&params)
+		for i.Next() {
+			if !stream(i.BalanceTransaction()) {
+				break
+			}
+		}
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_balance_transaction.listBalanceTransactions", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getBalanceTransaction(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_balance_transaction.getBalanceTransaction", "connection_error", err)
+		return nil, err
+	}
+	quals := d.EqualsQuals
+	id := quals["id"].GetStringValue()
+	params := &stripe.BalanceTransactionParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.BalanceTransactions.Get(id, params)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_balance_transaction.getBalanceTransaction", "query_error", err, "id", id)
+		return nil, err
+	}
+	return item, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/client"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
@@ -20,12 +21,19 @@ func tableStripeCharge(ctx context.Context) *plugin.Table {
 				{Name: "payment_intent", Require: plugin.Optional, Operators: []string{"="}},
 				{Name: "transfer_group", Require: plugin.Optional, Operators: []string{"="}},
 				{Name: "customer", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "amount", Require: plugin.Optional, Operators: []string{"=", "<>", ">", ">=", "<", "<="}},
+				{Name: "currency", Require: plugin.Optional, Operators: []string{"=", "<>"}},
+				{Name: "disputed", Require: plugin.Optional, Operators: []string{"=", "<>"}},
+				{Name: "metadata", Require: plugin.Optional, Operators: []string{"@>"}},
+				{Name: "refunded", Require: plugin.Optional, Operators: []string{"=", "<>"}},
+				{Name: "status", Require: plugin.Optional, Operators: []string{"=", "<>"}},
 			},
 		},
 		Get: &plugin.GetConfig{
 			Hydrate:    getCharge,
 			KeyColumns: plugin.SingleColumn("id"),
 		},
+		GetMatrixItemFunc: connectedAccountMatrix,
 		Columns: commonColumns([]*plugin.Column{
 			// Basic fields
 			{
@@ -94,11 +102,6 @@ func tableStripeCharge(ctx context.Context) *plugin.Table {
 				Description: "Failure message if the charge failed.",
 				Type:        proto.ColumnType_STRING,
 			},
-			{
-				Name:        "livemode",
-				Description: "Indicates whether the charge was created in live mode.",
-				Type:        proto.ColumnType_BOOL,
-			},
 			{
 				Name:        "paid",
 				Description: "Indicates whether the charge was paid.",
@@ -240,16 +243,37 @@ func tableStripeCharge(ctx context.Context) *plugin.Table {
 	}
 }
 
+// chargeSearchFields are the charge columns that can be filtered using the
+// Search API
+var chargeSearchFields = []searchField{
+	{Column: "amount", Field: "amount", Type: searchFieldNumber},
+	{Column: "created", Field: "created", Type: searchFieldTimestamp, ListFilter: true},
+	{Column: "currency", Field: "currency", Type: searchFieldString},
+	{Column: "customer", Field: "customer", Type: searchFieldString, ListFilter: true},
+	{Column: "disputed", Field: "disputed", Type: searchFieldBool},
+	{Column: "metadata", Field: "metadata", Type: searchFieldMetadata},
+	{Column: "refunded", Field: "refunded", Type: searchFieldBool},
+	{Column: "status", Field: "status", Type: searchFieldString},
+}
+
 func listCharges(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
 	conn, err := connect(ctx, d)
 	if err != nil {
 		plugin.Logger(ctx).Error("stripe_charge.listCharges", "connection_error", err)
 		return nil, err
 	}
+
+	// Filters the List endpoint does not support are pushed down using the
+	// Search API instead
+	if query := buildSearchQuery(d, chargeSearchFields, "payment_intent", "transfer_group"); query != "" {
+		return searchCharges(ctx, d, conn, query)
+	}
+
 	params := &stripe.ChargeListParams{
 		ListParams: stripe.ListParams{
-			Context: ctx,
-			Limit:   stripe.Int64(100),
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
 		},
 	}
 
@@ -264,39 +288,6 @@ func listCharges(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 		params.TransferGroup = stripe.String(q["transfer_group"].GetStringValue())
 	}
 
-	// Comparison values
-	quals := d.Quals
-
-	if quals["created"] != nil {
-		for _, q := range quals["created"].Quals {
-			tsSecs := q.Value.GetTimestampValue().GetSeconds()
-			switch q.Operator {
-			case ">":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.GreaterThan = tsSecs
-			case ">=":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.GreaterThanOrEqual = tsSecs
-			case "=":
-				params.Created = stripe.Int64(tsSecs)
-			case "<=":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.LesserThanOrEqual = tsSecs
-			case "<":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.LesserThan = tsSecs
-			}
-		}
-	}
-
 	limit := d.QueryContext.Limit
 	if d.QueryContext.Limit != nil {
 		if *limit < *params.ListParams.Limit {
@@ -304,19 +295,23 @@ func listCharges(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 		}
 	}
 
-	var count int64
-	i := conn.Charges.List(params)
-	for i.Next() {
-		d.StreamListItem(ctx, i.Charge())
-		count++
-		if limit != nil {
-			if count >= *limit {
+	err = listQueryRanges(ctx, d, []string{"created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.Created, params.CreatedRange = r["created"].listParams()
+		i := conn.Charges.List(&params)
+		for i.Next() {
+			if !stream(i.Charge()) {
 				break
 			}
 		}
-	}
-	if err := i.Err(); err != nil {
-		plugin.Logger(ctx).Error("stripe_charge.listCharges", "query_error", err, "params", params, "i", i)
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_charge.listCharges", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -331,10 +326,52 @@ func getCharge(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 	}
 	quals := d.EqualsQuals
 	id := quals["id"].GetStringValue()
-	item, err := conn.Charges.Get(id, &stripe.ChargeParams{})
+	params := &stripe.ChargeParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.Charges.Get(id, params)
 	if err != nil {
 		plugin.Logger(ctx).Error("stripe_charge.getCharge", "query_error", err, "id", id)
 		return nil, err
 	}
 	return item, nil
 }
+
+func searchCharges(ctx context.Context, d *plugin.QueryData, conn *client.API, query string) (interface{}, error) {
+	params := &stripe.ChargeSearchParams{
+		SearchParams: stripe.SearchParams{
+			Context:       ctx,
+			Query:         query,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.SearchParams.Limit {
+			params.SearchParams.Limit = limit
+		}
+	}
+
+	var count int64
+	i := conn.Charges.Search(params)
+	for i.Next() {
+		d.StreamListItem(ctx, i.Charge())
+		count++
+		if limit != nil {
+			if count >= *limit {
+				break
+			}
+		}
+	}
+	if err := i.Err(); err != nil {
+		plugin.Logger(ctx).Error("stripe_charge.searchCharges", "query_error", err, "query", query)
+		return nil, err
+	}
+
+	return nil, nil
+}
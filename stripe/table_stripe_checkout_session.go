@@ -0,0 +1,293 @@
+package stripe
+
+import (
+	"context"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripeCheckoutSession(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_checkout_session",
+		Description: "Checkout Sessions are the sessions of customers paying through Checkout, from creation until completion or expiry.",
+		List: &plugin.ListConfig{
+			Hydrate: listCheckoutSessions,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "created", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
+				{Name: "customer", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "payment_intent", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "payment_link", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "status", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "subscription", Require: plugin.Optional, Operators: []string{"="}},
+			},
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getCheckoutSession,
+			KeyColumns: plugin.SingleColumn("id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			// Basic fields
+			{
+				Name:        "id",
+				Description: "Unique identifier for the Checkout Session.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "mode",
+				Description: "The mode of the Checkout Session, one of payment, setup or subscription.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "status",
+				Description: "The status of the Checkout Session, one of open, complete or expired.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "payment_status",
+				Description: "The payment status of the Checkout Session, one of paid, unpaid or no_payment_required.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "amount_subtotal",
+				Description: "Total of all items before discounts or taxes are applied.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("AmountSubtotal"),
+			},
+			{
+				Name:        "amount_total",
+				Description: "Total of all items after discounts and taxes are applied.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("AmountTotal"),
+			},
+			{
+				Name:        "cancel_url",
+				Description: "The URL the customer is directed to if they decide to cancel payment and return to your website.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CancelURL"),
+			},
+			{
+				Name:        "client_reference_id",
+				Description: "A unique string to reference the Checkout Session, such as a customer or cart ID, used to reconcile the session with your internal systems.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "created",
+				Description: "Time at which the Checkout Session was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Created").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "currency",
+				Description: "Three-letter ISO currency code, in lowercase.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "customer",
+				Description: "ID of the customer of the Checkout Session, if it exists.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Customer.ID"),
+			},
+			{
+				Name:        "customer_creation",
+				Description: "Whether a customer is created when the Checkout Session completes, either always or if_required.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "customer_email",
+				Description: "The email address the Checkout Session was created with, to prefill the customer's email.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "expires_at",
+				Description: "The time at which the Checkout Session will expire.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("ExpiresAt").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "invoice",
+				Description: "ID of the invoice created by the Checkout Session, if it exists.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Invoice.ID"),
+			},
+			{
+				Name:        "locale",
+				Description: "The IETF language tag of the locale Checkout is displayed in.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "payment_intent",
+				Description: "ID of the PaymentIntent of Checkout Sessions in payment mode.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PaymentIntent.ID"),
+			},
+			{
+				Name:        "payment_link",
+				Description: "ID of the Payment Link that created the Checkout Session.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PaymentLink.ID"),
+			},
+			{
+				Name:        "recovered_from",
+				Description: "ID of the expired Checkout Session this session was recovered from.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "setup_intent",
+				Description: "ID of the SetupIntent of Checkout Sessions in setup mode.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("SetupIntent.ID"),
+			},
+			{
+				Name:        "submit_type",
+				Description: "The type of transaction being performed, which customizes the text on the pay button.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "subscription",
+				Description: "ID of the subscription of Checkout Sessions in subscription mode.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Subscription.ID"),
+			},
+			{
+				Name:        "success_url",
+				Description: "The URL the customer is directed to after the payment or subscription creation is successful.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("SuccessURL"),
+			},
+			{
+				Name:        "ui_mode",
+				Description: "The UI mode of the Checkout Session, either hosted or embedded.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("UIMode"),
+			},
+			{
+				Name:        "url",
+				Description: "The URL to the Checkout Session, while it is open.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("URL"),
+			},
+
+			// JSON columns for complex data
+			{
+				Name:        "after_expiration",
+				Description: "The recovery configuration of the Checkout Session once it expires.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "customer_details",
+				Description: "The customer details, including the address, email, name and phone, collected by the Checkout Session.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "metadata",
+				Description: "Set of key-value pairs attached to the Checkout Session.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "payment_method_types",
+				Description: "The payment method types the customer can use.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "shipping_details",
+				Description: "The shipping details collected by the Checkout Session.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "total_details",
+				Description: "The breakdown of the discounts, shipping and taxes in amount_total.",
+				Type:        proto.ColumnType_JSON,
+			},
+		}),
+	}
+}
+
+func listCheckoutSessions(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_checkout_session.listCheckoutSessions", "connection_error", err)
+		return nil, err
+	}
+	params := &stripe.CheckoutSessionListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	q := d.EqualsQuals
+	if q["customer"] != nil {
+		params.Customer = stripe.String(q["customer"].GetStringValue())
+	}
+	if q["payment_intent"] != nil {
+		params.PaymentIntent = stripe.String(q["payment_intent"].GetStringValue())
+	}
+	if q["payment_link"] != nil {
+		params.PaymentLink = stripe.String(q["payment_link"].GetStringValue())
+	}
+	if q["subscription"] != nil {
+		params.Subscription = stripe.String(q["subscription"].GetStringValue())
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	err = listQueryRanges(ctx, d, []string{"created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		// The params of this stripe-go version do not model the created and
+		// status filters of Checkout Sessions
+		params.Filters = stripe.Filters{}
+		r["created"].addFilters(&params.Filters, "created")
+		if q["status"] != nil {
+			params.Filters.AddFilter("status", "", q["status"].GetStringValue())
+		}
+		i := conn.CheckoutSessions.List(&params)
+		for i.Next() {
+			if !stream(i.CheckoutSession()) {
+				break
+			}
+		}
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_checkout_session.listCheckoutSessions", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getCheckoutSession(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_checkout_session.getCheckoutSession", "connection_error", err)
+		return nil, err
+	}
+	quals := d.EqualsQuals
+	id := quals["id"].GetStringValue()
+	params := &stripe.CheckoutSessionParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.CheckoutSessions.Get(id, params)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_checkout_session.getCheckoutSession", "query_error", err, "id", id)
+		return nil, err
+	}
+	return item, nil
+}
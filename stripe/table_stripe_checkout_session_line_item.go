@@ -0,0 +1,170 @@
+package stripe
+
+import (
+	"context"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripeCheckoutSessionLineItem(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_checkout_session_line_item",
+		Description: "Line items of Stripe Checkout Sessions.",
+		List: &plugin.ListConfig{
+			Hydrate:    listCheckoutSessionLineItems,
+			KeyColumns: plugin.SingleColumn("session_id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			{
+				Name:        "id",
+				Description: "Unique identifier for the line item.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("LineItem.ID"),
+			},
+			{
+				Name:        "session_id",
+				Description: "ID of the Checkout Session the line item belongs to.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("SessionID"),
+			},
+			{
+				Name:        "amount_discount",
+				Description: "Total discount amount applied.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("LineItem.AmountDiscount"),
+			},
+			{
+				Name:        "amount_subtotal",
+				Description: "Total before any discounts or taxes are applied.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("LineItem.AmountSubtotal"),
+			},
+			{
+				Name:        "amount_tax",
+				Description: "Total tax amount applied.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("LineItem.AmountTax"),
+			},
+			{
+				Name:        "amount_total",
+				Description: "Total after discounts and taxes.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("LineItem.AmountTotal"),
+			},
+			{
+				Name:        "currency",
+				Description: "Three-letter ISO currency code, in lowercase.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("LineItem.Currency"),
+			},
+			{
+				Name:        "description",
+				Description: "An arbitrary string attached to the line item. Defaults to the product name.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("LineItem.Description"),
+			},
+			{
+				Name:        "price_id",
+				Description: "ID of the price of the line item.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("LineItem.Price.ID"),
+			},
+			{
+				Name:        "product_id",
+				Description: "ID of the product of the price of the line item.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("LineItem.Price.Product.ID"),
+			},
+			{
+				Name:        "quantity",
+				Description: "The quantity of products being purchased.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("LineItem.Quantity"),
+			},
+
+			// JSON columns for complex data
+			{
+				Name:        "discounts",
+				Description: "The discounts applied to the line item.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("LineItem.Discounts"),
+			},
+			{
+				Name:        "price",
+				Description: "The price used to generate the line item.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("LineItem.Price"),
+			},
+			{
+				Name:        "taxes",
+				Description: "The taxes applied to the line item.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("LineItem.Taxes"),
+			},
+		}),
+	}
+}
+
+// checkoutSessionLineItem is a line item along with the Checkout Session it
+// belongs to, which line items do not return.
+type checkoutSessionLineItem struct {
+	SessionID string
+	LineItem  *stripe.LineItem
+}
+
+func listCheckoutSessionLineItems(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_checkout_session_line_item.listCheckoutSessionLineItems", "connection_error", err)
+		return nil, err
+	}
+
+	sessionID := d.EqualsQuals["session_id"].GetStringValue()
+	if sessionID == "" {
+		return nil, nil
+	}
+
+	params := &stripe.CheckoutSessionListLineItemsParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+		Session: stripe.String(sessionID),
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	var count int64
+	i := conn.CheckoutSessions.ListLineItems(params)
+	for i.Next() {
+		d.StreamListItem(ctx, &checkoutSessionLineItem{
+			SessionID: sessionID,
+			LineItem:  i.LineItem(),
+		})
+		count++
+		if limit != nil {
+			if count >= *limit {
+				break
+			}
+		}
+	}
+	if err := i.Err(); err != nil {
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		plugin.Logger(ctx).Error("stripe_checkout_session_line_item.listCheckoutSessionLineItems", "query_error", err, "params", params, "i", i)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -23,6 +23,7 @@ func tableStripeCoupon(ctx context.Context) *plugin.Table {
 			Hydrate:    getCoupon,
 			KeyColumns: plugin.SingleColumn("id"),
 		},
+		GetMatrixItemFunc: connectedAccountMatrix,
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
 			{Name: "id", Type: proto.ColumnType_STRING, Description: "Unique identifier for the coupon."},
@@ -34,7 +35,6 @@ func tableStripeCoupon(ctx context.Context) *plugin.Table {
 			{Name: "deleted", Type: proto.ColumnType_BOOL, Description: "True if the customer is marked as deleted."},
 			{Name: "duration", Type: proto.ColumnType_STRING, Description: "One of forever, once, and repeating. Describes how long a customer who applies this coupon will get the discount."},
 			{Name: "duration_in_months", Type: proto.ColumnType_INT, Transform: transform.FromField("DurationInMonths"), Description: "If duration is repeating, the number of months the coupon applies. Null if coupon duration is forever or once."},
-			{Name: "livemode", Type: proto.ColumnType_BOOL, Description: "Has the value true if the coupon exists in live mode or the value false if the coupon exists in test mode."},
 			{Name: "max_redemptions", Type: proto.ColumnType_INT, Transform: transform.FromField("MaxRedemptions"), Description: "Maximum number of times this coupon can be redeemed, in total, across all customers, before it is no longer valid."},
 			{Name: "metadata", Type: proto.ColumnType_JSON, Description: "Set of key-value pairs that you can attach to an coupon. This can be useful for storing additional information about the coupon in a structured format."},
 			{Name: "percent_off", Type: proto.ColumnType_DOUBLE, Transform: transform.FromField("PercentOff"), Description: "Percent that will be taken off the subtotal of any invoices for this customer for the duration of the coupon. For example, a coupon with percent_off of 50 will make a $100 invoice $50 instead."},
@@ -54,43 +54,12 @@ func listCoupon(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 
 	params := &stripe.CouponListParams{
 		ListParams: stripe.ListParams{
-			Context: ctx,
-			Limit:   stripe.Int64(100),
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
 		},
 	}
 
-	quals := d.Quals
-
-	if quals["created"] != nil {
-		for _, q := range quals["created"].Quals {
-			tsSecs := q.Value.GetTimestampValue().GetSeconds()
-			switch q.Operator {
-			case ">":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.GreaterThan = tsSecs
-			case ">=":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.GreaterThanOrEqual = tsSecs
-			case "=":
-				params.Created = stripe.Int64(tsSecs)
-			case "<=":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.LesserThanOrEqual = tsSecs
-			case "<":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.LesserThan = tsSecs
-			}
-		}
-	}
-
 	limit := d.QueryContext.Limit
 	if d.QueryContext.Limit != nil {
 		if *limit < *params.ListParams.Limit {
@@ -98,19 +67,23 @@ func listCoupon(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 		}
 	}
 
-	var count int64
-	i := conn.Coupons.List(params)
-	for i.Next() {
-		d.StreamListItem(ctx, i.Coupon())
-		count++
-		if limit != nil {
-			if count >= *limit {
+	err = listQueryRanges(ctx, d, []string{"created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.Created, params.CreatedRange = r["created"].listParams()
+		i := conn.Coupons.List(&params)
+		for i.Next() {
+			if !stream(i.Coupon()) {
 				break
 			}
 		}
-	}
-	if err := i.Err(); err != nil {
-		plugin.Logger(ctx).Error("stripe_coupon.listCoupon", "query_error", err, "params", params, "i", i)
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_coupon.listCoupon", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -125,7 +98,13 @@ func getCoupon(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 	}
 	quals := d.EqualsQuals
 	id := quals["id"].GetStringValue()
-	item, err := conn.Coupons.Get(id, &stripe.CouponParams{})
+	params := &stripe.CouponParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.Coupons.Get(id, params)
 	if err != nil {
 		plugin.Logger(ctx).Error("stripe_coupon.getCoupon", "query_error", err, "id", id)
 		return nil, err
@@ -0,0 +1,298 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripeCreditNote(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_credit_note",
+		Description: "Credit notes adjust the amount of finalized invoices, before or after they are paid.",
+		List: &plugin.ListConfig{
+			Hydrate: listCreditNotes,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "customer", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "invoice", Require: plugin.Optional, Operators: []string{"="}},
+			},
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getCreditNote,
+			KeyColumns: plugin.SingleColumn("id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			// Basic fields
+			{
+				Name:        "id",
+				Description: "Unique identifier for the credit note.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNote.ID"),
+			},
+			{
+				Name:        "amount",
+				Description: "The integer amount in the smallest currency unit representing the total amount of the credit note, including tax.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("CreditNote.Amount"),
+			},
+			{
+				Name:        "amount_shipping",
+				Description: "This is the sum of all the shipping amounts.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("CreditNote.AmountShipping"),
+			},
+			{
+				Name:        "created",
+				Description: "Time at which the credit note was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("CreditNote.Created").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "currency",
+				Description: "Three-letter ISO currency code, in lowercase.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNote.Currency"),
+			},
+			{
+				Name:        "customer",
+				Description: "ID of the customer.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNote.Customer.ID"),
+			},
+			{
+				Name:        "customer_balance_transaction",
+				Description: "ID of the customer balance transaction related to this credit note.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNote.CustomerBalanceTransaction.ID"),
+			},
+			{
+				Name:        "discount_amount",
+				Description: "The integer amount in the smallest currency unit representing the total amount of discount that was credited.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("CreditNote.DiscountAmount"),
+			},
+			{
+				Name:        "effective_at",
+				Description: "The date when this credit note is in effect. Same as created unless overwritten.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("CreditNote.EffectiveAt").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "invoice",
+				Description: "ID of the invoice.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNote.Invoice.ID"),
+			},
+			{
+				Name:        "memo",
+				Description: "Customer-facing text that appears on the credit note PDF.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNote.Memo"),
+			},
+			{
+				Name:        "number",
+				Description: "A unique number that identifies this particular credit note and appears on the PDF of the credit note and its associated invoice.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNote.Number"),
+			},
+			{
+				Name:        "out_of_band_amount",
+				Description: "Amount that was credited outside of Stripe.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("CreditNote.OutOfBandAmount"),
+			},
+			{
+				Name:        "pdf",
+				Description: "The link to download the PDF of the credit note.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNote.PDF"),
+			},
+			{
+				Name:        "reason",
+				Description: "Reason for issuing this credit note, one of duplicate, fraudulent, order_change, or product_unsatisfactory.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNote.Reason"),
+			},
+			{
+				Name:        "refund",
+				Description: "ID of the refund related to this credit note.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNote.Refund.ID"),
+			},
+			{
+				Name:        "status",
+				Description: "Status of this credit note, one of issued or void.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNote.Status"),
+			},
+			{
+				Name:        "subtotal",
+				Description: "The integer amount in the smallest currency unit representing the amount of the credit note, excluding exclusive tax and invoice level discounts.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("CreditNote.Subtotal"),
+			},
+			{
+				Name:        "subtotal_excluding_tax",
+				Description: "The integer amount in the smallest currency unit representing the amount of the credit note, excluding all tax and invoice level discounts.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("CreditNote.SubtotalExcludingTax"),
+			},
+			{
+				Name:        "total",
+				Description: "The integer amount in the smallest currency unit representing the total amount of the credit note, including tax and all discounts.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("CreditNote.Total"),
+			},
+			{
+				Name:        "total_excluding_tax",
+				Description: "The integer amount in the smallest currency unit representing the total amount of the credit note, excluding tax, but including discounts.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("CreditNote.TotalExcludingTax"),
+			},
+			{
+				Name:        "type",
+				Description: "Type of this credit note, one of pre_payment or post_payment. A pre_payment credit note means it was issued when the invoice was open. A post_payment credit note means it was issued when the invoice was paid.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNote.Type"),
+			},
+			{
+				Name:        "voided_at",
+				Description: "The time that the credit note was voided.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("CreditNote.VoidedAt").Transform(transform.UnixToTimestamp),
+			},
+
+			// JSON columns for complex data
+			{
+				Name:        "discount_amounts",
+				Description: "The aggregate amounts calculated per discount for all line items.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("CreditNote.DiscountAmounts"),
+			},
+			{
+				Name:        "metadata",
+				Description: "Set of key-value pairs attached to the credit note.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("CreditNote.Metadata"),
+			},
+			{
+				Name:        "shipping_cost",
+				Description: "The details of the cost of shipping, including the ShippingRate applied to the invoice.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("CreditNote.ShippingCost"),
+			},
+			{
+				Name:        "tax_amounts",
+				Description: "The aggregate amounts calculated per tax rate for all line items.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("TaxAmounts"),
+			},
+		}),
+	}
+}
+
+// creditNote is a credit note along with its version dependent fields.
+type creditNote struct {
+	CreditNote *stripe.CreditNote
+	TaxAmounts interface{}
+}
+
+// creditNoteFields are the fields of a credit note that depend on the API
+// version.
+type creditNoteFields struct {
+	TotalTaxes json.RawMessage `json:"total_taxes"`
+}
+
+func newCreditNote(item *stripe.CreditNote, f creditNoteFields) *creditNote {
+	row := &creditNote{CreditNote: item}
+	if item.TaxAmounts != nil {
+		row.TaxAmounts = item.TaxAmounts
+	}
+	setCreditNoteVersionedFields(row, f)
+	return row
+}
+
+func listCreditNotes(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_credit_note.listCreditNotes", "connection_error", err)
+		return nil, err
+	}
+	params := &stripe.CreditNoteListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	q := d.EqualsQuals
+	if q["customer"] != nil {
+		params.Customer = stripe.String(q["customer"].GetStringValue())
+	}
+	if q["invoice"] != nil {
+		params.Invoice = stripe.String(q["invoice"].GetStringValue())
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	var page rawPage[creditNoteFields]
+	var count int64
+	i := conn.CreditNotes.List(params)
+	for i.Next() {
+		item := i.CreditNote()
+		d.StreamListItem(ctx, newCreditNote(item, page.get(i.CreditNoteList().LastResponse, item.ID)))
+		count++
+		if limit != nil {
+			if count >= *limit {
+				break
+			}
+		}
+	}
+	if err := i.Err(); err != nil {
+		plugin.Logger(ctx).Error("stripe_credit_note.listCreditNotes", "query_error", err, "params", params, "i", i)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getCreditNote(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_credit_note.getCreditNote", "connection_error", err)
+		return nil, err
+	}
+	quals := d.EqualsQuals
+	id := quals["id"].GetStringValue()
+	params := &stripe.CreditNoteParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.CreditNotes.Get(id, params)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_credit_note.getCreditNote", "query_error", err, "id", id)
+		return nil, err
+	}
+	return newCreditNote(item, decodeRawFields[creditNoteFields](item.LastResponse)), nil
+}
+
+// setCreditNoteVersionedFields sets the taxes of a credit note from its total
+// taxes, for API versions that no longer return its tax amounts.
+func setCreditNoteVersionedFields(c *creditNote, f creditNoteFields) {
+	if c.TaxAmounts == nil {
+		c.TaxAmounts = rawValue(f.TotalTaxes)
+	}
+}
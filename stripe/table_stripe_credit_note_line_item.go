@@ -0,0 +1,198 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripeCreditNoteLineItem(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_credit_note_line_item",
+		Description: "Line items of Stripe credit notes.",
+		List: &plugin.ListConfig{
+			Hydrate:    listCreditNoteLineItems,
+			KeyColumns: plugin.SingleColumn("credit_note_id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			{
+				Name:        "id",
+				Description: "Unique identifier for the line item.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNoteLineItem.ID"),
+			},
+			{
+				Name:        "credit_note_id",
+				Description: "ID of the credit note the line item belongs to.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNoteID"),
+			},
+			{
+				Name:        "amount",
+				Description: "The integer amount in the smallest currency unit representing the gross amount being credited for this line item, excluding (exclusive) tax and discounts.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("CreditNoteLineItem.Amount"),
+			},
+			{
+				Name:        "amount_excluding_tax",
+				Description: "The integer amount in the smallest currency unit representing the amount being credited for this line item, excluding all tax and discounts.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("CreditNoteLineItem.AmountExcludingTax"),
+			},
+			{
+				Name:        "description",
+				Description: "Description of the item being credited.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNoteLineItem.Description"),
+			},
+			{
+				Name:        "discount_amount",
+				Description: "The integer amount in the smallest currency unit representing the discount being credited for this line item.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("CreditNoteLineItem.DiscountAmount"),
+			},
+			{
+				Name:        "invoice_line_item",
+				Description: "ID of the invoice line item being credited.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNoteLineItem.InvoiceLineItem"),
+			},
+			{
+				Name:        "quantity",
+				Description: "The number of units of product being credited.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("CreditNoteLineItem.Quantity"),
+			},
+			{
+				Name:        "type",
+				Description: "The type of the credit note line item, one of invoice_line_item or custom_line_item.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CreditNoteLineItem.Type"),
+			},
+			{
+				Name:        "unit_amount",
+				Description: "The cost of each unit of product being credited.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("CreditNoteLineItem.UnitAmount"),
+			},
+			{
+				Name:        "unit_amount_decimal",
+				Description: "Same as unit_amount, but contains a decimal value with at most 12 decimal places.",
+				Type:        proto.ColumnType_DOUBLE,
+				Transform:   transform.FromField("CreditNoteLineItem.UnitAmountDecimal"),
+			},
+			{
+				Name:        "unit_amount_excluding_tax",
+				Description: "The amount in the smallest currency unit representing the unit amount being credited for this line item, excluding all tax and discounts.",
+				Type:        proto.ColumnType_DOUBLE,
+				Transform:   transform.FromField("CreditNoteLineItem.UnitAmountExcludingTax"),
+			},
+
+			// JSON columns for complex data
+			{
+				Name:        "discount_amounts",
+				Description: "The amount of discount calculated per discount for this line item.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("CreditNoteLineItem.DiscountAmounts"),
+			},
+			{
+				Name:        "tax_amounts",
+				Description: "The amount of tax calculated per tax rate for this line item.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("TaxAmounts"),
+			},
+			{
+				Name:        "tax_rates",
+				Description: "The tax rates which apply to the line item.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("CreditNoteLineItem.TaxRates"),
+			},
+		}),
+	}
+}
+
+// creditNoteLineItem is a line item along with the credit note it belongs
+// to, which line items do not return, and its version dependent fields.
+type creditNoteLineItem struct {
+	CreditNoteID       string
+	CreditNoteLineItem *stripe.CreditNoteLineItem
+	TaxAmounts         interface{}
+}
+
+// creditNoteLineItemFields are the fields of a credit note line item that
+// depend on the API version.
+type creditNoteLineItemFields struct {
+	Taxes json.RawMessage `json:"taxes"`
+}
+
+func listCreditNoteLineItems(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_credit_note_line_item.listCreditNoteLineItems", "connection_error", err)
+		return nil, err
+	}
+
+	creditNoteID := d.EqualsQuals["credit_note_id"].GetStringValue()
+	if creditNoteID == "" {
+		return nil, nil
+	}
+
+	params := &stripe.CreditNoteListLinesParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+		CreditNote: stripe.String(creditNoteID),
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	var page rawPage[creditNoteLineItemFields]
+	var count int64
+	i := conn.CreditNotes.ListLines(params)
+	for i.Next() {
+		item := &creditNoteLineItem{
+			CreditNoteID:       creditNoteID,
+			CreditNoteLineItem: i.CreditNoteLineItem(),
+		}
+		if item.CreditNoteLineItem.TaxAmounts != nil {
+			item.TaxAmounts = item.CreditNoteLineItem.TaxAmounts
+		}
+		setCreditNoteLineItemVersionedFields(item, page.get(i.CreditNoteLineItemList().LastResponse, item.CreditNoteLineItem.ID))
+		d.StreamListItem(ctx, item)
+		count++
+		if limit != nil {
+			if count >= *limit {
+				break
+			}
+		}
+	}
+	if err := i.Err(); err != nil {
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		plugin.Logger(ctx).Error("stripe_credit_note_line_item.listCreditNoteLineItems", "query_error", err, "params", params, "i", i)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// setCreditNoteLineItemVersionedFields sets the taxes of a credit note line
+// item, for API versions that no longer return its tax amounts.
+func setCreditNoteLineItemVersionedFields(i *creditNoteLineItem, f creditNoteLineItemFields) {
+	if i.TaxAmounts == nil {
+		i.TaxAmounts = rawValue(f.Taxes)
+	}
+}
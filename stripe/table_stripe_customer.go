@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/client"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
@@ -17,13 +18,17 @@ func tableStripeCustomer(ctx context.Context) *plugin.Table {
 			Hydrate: listCustomer,
 			KeyColumns: []*plugin.KeyColumn{
 				{Name: "created", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
-				{Name: "email", Require: plugin.Optional},
+				{Name: "email", Operators: []string{"=", "<>", "~~"}, Require: plugin.Optional},
+				{Name: "metadata", Operators: []string{"@>"}, Require: plugin.Optional},
+				{Name: "name", Operators: []string{"=", "<>", "~~"}, Require: plugin.Optional},
+				{Name: "phone", Operators: []string{"=", "<>", "~~"}, Require: plugin.Optional},
 			},
 		},
 		Get: &plugin.GetConfig{
 			Hydrate:    getCustomer,
 			KeyColumns: plugin.SingleColumn("id"),
 		},
+		GetMatrixItemFunc: connectedAccountMatrix,
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
 			{Name: "id", Type: proto.ColumnType_STRING, Description: "Unique identifier for the customer."},
@@ -40,7 +45,6 @@ func tableStripeCustomer(ctx context.Context) *plugin.Table {
 			{Name: "discount", Type: proto.ColumnType_JSON, Description: "Describes the current discount active on the customer, if there is one."},
 			{Name: "invoice_prefix", Type: proto.ColumnType_STRING, Description: "The prefix for the customer used to generate unique invoice numbers."},
 			{Name: "invoice_settings", Type: proto.ColumnType_JSON, Description: "The customer’s default invoice settings."},
-			{Name: "livemode", Type: proto.ColumnType_BOOL, Description: "Has the value true if the object exists in live mode or the value false if the object exists in test mode."},
 			{Name: "metadata", Type: proto.ColumnType_JSON, Description: "Set of key-value pairs that you can attach to an object. This can be useful for storing additional information about the object in a structured format."},
 			{Name: "name", Type: proto.ColumnType_STRING, Description: "The customer’s full name or business name."},
 			{Name: "next_invoice_sequence", Type: proto.ColumnType_INT, Description: "The suffix of the customer’s next invoice number, e.g., 0001."},
@@ -55,16 +59,34 @@ func tableStripeCustomer(ctx context.Context) *plugin.Table {
 	}
 }
 
+// customerSearchFields are the customer columns that can be filtered using the
+// Search API
+var customerSearchFields = []searchField{
+	{Column: "created", Field: "created", Type: searchFieldTimestamp, ListFilter: true},
+	{Column: "email", Field: "email", Type: searchFieldString, ListFilter: true},
+	{Column: "metadata", Field: "metadata", Type: searchFieldMetadata},
+	{Column: "name", Field: "name", Type: searchFieldString},
+	{Column: "phone", Field: "phone", Type: searchFieldString},
+}
+
 func listCustomer(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
 	conn, err := connect(ctx, d)
 	if err != nil {
 		plugin.Logger(ctx).Error("stripe_customer.listCustomer", "connection_error", err)
 		return nil, err
 	}
+
+	// Filters the List endpoint does not support are pushed down using the
+	// Search API instead
+	if query := buildSearchQuery(d, customerSearchFields); query != "" {
+		return searchCustomers(ctx, d, conn, query)
+	}
+
 	params := &stripe.CustomerListParams{
 		ListParams: stripe.ListParams{
-			Context: ctx,
-			Limit:   stripe.Int64(100),
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
 		},
 	}
 
@@ -73,39 +95,6 @@ func listCustomer(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 		params.Email = stripe.String(q["email"].GetStringValue())
 	}
 
-	// Comparison values
-	quals := d.Quals
-
-	if quals["created"] != nil {
-		for _, q := range quals["created"].Quals {
-			tsSecs := q.Value.GetTimestampValue().GetSeconds()
-			switch q.Operator {
-			case ">":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.GreaterThan = tsSecs
-			case ">=":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.GreaterThanOrEqual = tsSecs
-			case "=":
-				params.Created = stripe.Int64(tsSecs)
-			case "<=":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.LesserThanOrEqual = tsSecs
-			case "<":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.LesserThan = tsSecs
-			}
-		}
-	}
-
 	limit := d.QueryContext.Limit
 	if d.QueryContext.Limit != nil {
 		if *limit < *params.ListParams.Limit {
@@ -113,19 +102,23 @@ func listCustomer(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 		}
 	}
 
-	var count int64
-	i := conn.Customers.List(params)
-	for i.Next() {
-		d.StreamListItem(ctx, i.Customer())
-		count++
-		if limit != nil {
-			if count >= *limit {
+	err = listQueryRanges(ctx, d, []string{"created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.Created, params.CreatedRange = r["created"].listParams()
+		i := conn.Customers.List(&params)
+		for i.Next() {
+			if !stream(i.Customer()) {
 				break
 			}
 		}
-	}
-	if err := i.Err(); err != nil {
-		plugin.Logger(ctx).Error("stripe_customer.listCustomer", "query_error", err, "params", params, "i", i)
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_customer.listCustomer", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -140,10 +133,52 @@ func getCustomer(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 	}
 	quals := d.EqualsQuals
 	id := quals["id"].GetStringValue()
-	item, err := conn.Customers.Get(id, &stripe.CustomerParams{})
+	params := &stripe.CustomerParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.Customers.Get(id, params)
 	if err != nil {
 		plugin.Logger(ctx).Error("stripe_customer.getCustomer", "query_error", err, "id", id)
 		return nil, err
 	}
 	return item, nil
 }
+
+func searchCustomers(ctx context.Context, d *plugin.QueryData, conn *client.API, query string) (interface{}, error) {
+	params := &stripe.CustomerSearchParams{
+		SearchParams: stripe.SearchParams{
+			Context:       ctx,
+			Query:         query,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.SearchParams.Limit {
+			params.SearchParams.Limit = limit
+		}
+	}
+
+	var count int64
+	i := conn.Customers.Search(params)
+	for i.Next() {
+		d.StreamListItem(ctx, i.Customer())
+		count++
+		if limit != nil {
+			if count >= *limit {
+				break
+			}
+		}
+	}
+	if err := i.Err(); err != nil {
+		plugin.Logger(ctx).Error("stripe_customer.searchCustomers", "query_error", err, "query", query)
+		return nil, err
+	}
+
+	return nil, nil
+}
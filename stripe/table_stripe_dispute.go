@@ -0,0 +1,193 @@
+package stripe
+
+import (
+	"context"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripeDispute(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_dispute",
+		Description: "Disputes (chargebacks) raised by customers against charges with their card issuer.",
+		List: &plugin.ListConfig{
+			Hydrate: listDisputes,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "created", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
+				{Name: "charge", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "payment_intent", Require: plugin.Optional, Operators: []string{"="}},
+			},
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getDispute,
+			KeyColumns: plugin.SingleColumn("id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			// Basic fields
+			{
+				Name:        "id",
+				Description: "Unique identifier for the dispute.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "amount",
+				Description: "Disputed amount, in the smallest currency unit. Usually the amount of the charge, but it can differ.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "charge",
+				Description: "ID of the charge that is disputed.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Charge.ID"),
+			},
+			{
+				Name:        "created",
+				Description: "Timestamp when the dispute was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Created").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "currency",
+				Description: "Currency of the dispute.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "evidence_due_by",
+				Description: "Date by which evidence must be submitted in order to successfully challenge the dispute.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("EvidenceDetails.DueBy").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "is_charge_refundable",
+				Description: "If true, it is still possible to refund the disputed payment. Once the payment has been fully refunded, no further funds will be withdrawn from your Stripe account as a result of this dispute.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "network_reason_code",
+				Description: "Network-dependent reason code for the dispute.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "payment_intent",
+				Description: "ID of the payment intent that is disputed.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PaymentIntent.ID"),
+			},
+			{
+				Name:        "reason",
+				Description: "Reason given by the cardholder for the dispute (e.g., duplicate, fraudulent, product_not_received, subscription_canceled).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "status",
+				Description: "Current status of the dispute (e.g., warning_needs_response, needs_response, under_review, won, lost).",
+				Type:        proto.ColumnType_STRING,
+			},
+
+			// JSON columns for complex data
+			{
+				Name:        "balance_transactions",
+				Description: "List of zero, one, or two balance transactions that show funds withdrawn and reinstated to your Stripe account as a result of this dispute.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "evidence",
+				Description: "Evidence provided to respond to the dispute.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "evidence_details",
+				Description: "Information about the evidence submission, including the due date, whether evidence has been staged and the submission count.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "metadata",
+				Description: "Metadata associated with the dispute.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "payment_method_details",
+				Description: "Details about the payment method used for the disputed payment.",
+				Type:        proto.ColumnType_JSON,
+			},
+		}),
+	}
+}
+
+func listDisputes(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_dispute.listDisputes", "connection_error", err)
+		return nil, err
+	}
+	params := &stripe.DisputeListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	q := d.EqualsQuals
+	if q["charge"] != nil {
+		params.Charge = stripe.String(q["charge"].GetStringValue())
+	}
+	if q["payment_intent"] != nil {
+		params.PaymentIntent = stripe.String(q["payment_intent"].GetStringValue())
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	err = listQueryRanges(ctx, d, []string{"created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.Created, params.CreatedRange = r["created"].listParams()
+		i := conn.Disputes.List(&params)
+		for i.Next() {
+			if !stream(i.Dispute()) {
+				break
+			}
+		}
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_dispute.listDisputes", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getDispute(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_dispute.getDispute", "connection_error", err)
+		return nil, err
+	}
+	quals := d.EqualsQuals
+	id := quals["id"].GetStringValue()
+	params := &stripe.DisputeParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.Disputes.Get(id, params)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_dispute.getDispute", "query_error", err, "id", id)
+		return nil, err
+	}
+	return item, nil
+}
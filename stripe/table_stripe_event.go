@@ -0,0 +1,228 @@
+package stripe
+
+import (
+	"context"
+	"strings"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripeEvent(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_event",
+		Description: "Events are notifications of changes to Stripe objects, retained for 30 days.",
+		List: &plugin.ListConfig{
+			Hydrate: listEvents,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "created", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
+				{Name: "delivery_success", Operators: []string{"="}, Require: plugin.Optional},
+				{Name: "type", Operators: []string{"=", "~~"}, Require: plugin.Optional},
+			},
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getEvent,
+			KeyColumns: plugin.SingleColumn("id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			// Basic fields
+			{
+				Name:        "id",
+				Description: "Unique identifier for the event.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "type",
+				Description: "Description of the event, e.g. invoice.created or charge.refunded.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "created",
+				Description: "Time at which the event was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Created").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "account",
+				Description: "The connected account that originated the event.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "data_api_version",
+				Description: "The Stripe API version used to render data_object and data_previous_attributes.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("APIVersion"),
+			},
+			{
+				Name:        "delivery_success",
+				Description: "Whether all webhooks of the event were successfully delivered. Only populated when the query specifies delivery_success.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromQual("delivery_success"),
+			},
+			{
+				Name:        "object_id",
+				Description: "ID of the object the event relates to.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Data.Object.id"),
+			},
+			{
+				Name:        "object_type",
+				Description: "Type of the object the event relates to, e.g. invoice or subscription.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Data.Object.object"),
+			},
+			{
+				Name:        "pending_webhooks",
+				Description: "Number of webhooks that have not been successfully delivered yet.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("PendingWebhooks"),
+			},
+			{
+				Name:        "request_id",
+				Description: "ID of the API request that caused the event, or null for events caused automatically by Stripe.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Request.ID"),
+			},
+			{
+				Name:        "request_idempotency_key",
+				Description: "The idempotency key of the API request that caused the event.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Request.IdempotencyKey"),
+			},
+
+			// JSON columns for complex data
+			{
+				Name:        "data_object",
+				Description: "The object the event relates to, as it was after the event. For example, an invoice.created event holds the invoice.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Data.Object"),
+			},
+			{
+				Name:        "data_previous_attributes",
+				Description: "The names and previous values of the attributes that changed, for events of type *.updated.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Data.PreviousAttributes"),
+			},
+		}),
+	}
+}
+
+func listEvents(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_event.listEvents", "connection_error", err)
+		return nil, err
+	}
+	params := &stripe.EventListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	equalQuals := d.EqualsQuals
+	if equalQuals["delivery_success"] != nil {
+		params.DeliverySuccess = stripe.Bool(equalQuals["delivery_success"].GetBoolValue())
+	}
+	params.Type = eventTypeParam(d)
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	err = listQueryRanges(ctx, d, []string{"created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.Created, params.CreatedRange = r["created"].listParams()
+		i := conn.Events.List(&params)
+		for i.Next() {
+			if !stream(i.Event()) {
+				break
+			}
+		}
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_event.listEvents", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// eventTypeParam returns the type to list events of for the quals on type,
+// which may be a group of types such as invoice.*. Only the first qual that
+// can be expressed is pushed down, and Steampipe filters the rows on the
+// others.
+func eventTypeParam(d *plugin.QueryData) *string {
+	if d.Quals["type"] == nil {
+		return nil
+	}
+	for _, q := range d.Quals["type"].Quals {
+		switch q.Operator {
+		case "=":
+			if t := q.Value.GetStringValue(); t != "" {
+				return stripe.String(t)
+			}
+		case quals.QualOperatorLike:
+			if t := eventTypeWildcard(q.Value.GetStringValue()); t != "" {
+				return stripe.String(t)
+			}
+		}
+	}
+	return nil
+}
+
+// eventTypeWildcard translates a LIKE pattern on event types into a Stripe
+// type filter, or returns an empty string if it cannot be expressed. Stripe
+// only matches whole groups of types, such as invoice.* for invoice.%.
+// Patterns with an underscore or an escape are not translated, since LIKE
+// matches an underscore as any character and Stripe matches it literally.
+func eventTypeWildcard(pattern string) string {
+	if strings.ContainsAny(pattern, `_\`) {
+		return ""
+	}
+	prefix, wildcard := strings.CutSuffix(pattern, "%")
+	if strings.Contains(prefix, "%") {
+		return ""
+	}
+	if !wildcard {
+		return prefix
+	}
+	if !strings.HasSuffix(prefix, ".") {
+		return ""
+	}
+	return prefix + "*"
+}
+
+func getEvent(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_event.getEvent", "connection_error", err)
+		return nil, err
+	}
+	id := d.EqualsQuals["id"].GetStringValue()
+	params := &stripe.EventParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.Events.Get(id, params)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_event.getEvent", "query_error", err, "id", id)
+		return nil, err
+	}
+	return item, nil
+}
@@ -0,0 +1,311 @@
+package stripe
+
+import (
+	"context"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripeInvoiceItem(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_invoice_item",
+		Description: "Invoice items are one-off amounts added to the next invoice of a customer, or to a draft invoice.",
+		List: &plugin.ListConfig{
+			Hydrate: listInvoiceItems,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "created", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
+				{Name: "customer", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "invoice", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "pending", Require: plugin.Optional, Operators: []string{"=", "<>"}},
+			},
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getInvoiceItem,
+			KeyColumns: plugin.SingleColumn("id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			// Basic fields
+			{
+				Name:        "id",
+				Description: "Unique identifier for the invoice item.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("InvoiceItem.ID"),
+			},
+			{
+				Name:        "amount",
+				Description: "Amount in the smallest currency unit of the invoice item.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("InvoiceItem.Amount"),
+			},
+			{
+				Name:        "created",
+				Description: "Time at which the invoice item was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("InvoiceItem.Date").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "currency",
+				Description: "Three-letter ISO currency code, in lowercase.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("InvoiceItem.Currency"),
+			},
+			{
+				Name:        "customer",
+				Description: "ID of the customer who will be billed when this invoice item is billed.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("InvoiceItem.Customer.ID"),
+			},
+			{
+				Name:        "description",
+				Description: "An arbitrary string attached to the invoice item. Often useful for displaying to users.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("InvoiceItem.Description"),
+			},
+			{
+				Name:        "discountable",
+				Description: "If true, discounts will apply to this invoice item. Always false for prorations.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("InvoiceItem.Discountable"),
+			},
+			{
+				Name:        "invoice",
+				Description: "ID of the invoice this invoice item belongs to.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("InvoiceItem.Invoice.ID"),
+			},
+			{
+				Name:        "pending",
+				Description: "True if the invoice item is not yet attached to an invoice.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("Pending"),
+			},
+			{
+				Name:        "period_end",
+				Description: "End of the period the invoice item covers.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("InvoiceItem.Period.End").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "period_start",
+				Description: "Start of the period the invoice item covers.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("InvoiceItem.Period.Start").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "price_id",
+				Description: "ID of the price of the invoice item.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PriceID"),
+			},
+			{
+				Name:        "proration",
+				Description: "Whether the invoice item was created automatically as a proration adjustment when the customer switched plans.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("InvoiceItem.Proration"),
+			},
+			{
+				Name:        "quantity",
+				Description: "Quantity of units for the invoice item. If the invoice item is a proration, the quantity of the subscription that the proration was computed for.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("InvoiceItem.Quantity"),
+			},
+			{
+				Name:        "subscription",
+				Description: "ID of the subscription that the invoice item pertains to, if any.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("InvoiceItem.Subscription.ID"),
+			},
+			{
+				Name:        "subscription_item",
+				Description: "ID of the subscription item that the invoice item pertains to, if any.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("InvoiceItem.SubscriptionItem"),
+			},
+			{
+				Name:        "unit_amount",
+				Description: "Unit amount in the smallest currency unit of the invoice item.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("InvoiceItem.UnitAmount"),
+			},
+			{
+				Name:        "unit_amount_decimal",
+				Description: "Same as unit_amount, but contains a decimal value with at most 12 decimal places.",
+				Type:        proto.ColumnType_DOUBLE,
+				Transform:   transform.FromField("InvoiceItem.UnitAmountDecimal"),
+			},
+
+			// JSON columns for complex data
+			{
+				Name:        "discounts",
+				Description: "The discounts which apply to the invoice item.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("InvoiceItem.Discounts"),
+			},
+			{
+				Name:        "metadata",
+				Description: "Set of key-value pairs attached to the invoice item.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("InvoiceItem.Metadata"),
+			},
+			{
+				Name:        "period",
+				Description: "The period the invoice item covers, with its start and end.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("InvoiceItem.Period"),
+			},
+			{
+				Name:        "plan",
+				Description: "If the invoice item is a proration, the plan of the subscription that the proration was computed for.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("InvoiceItem.Plan"),
+			},
+			{
+				Name:        "price",
+				Description: "The price of the invoice item.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("InvoiceItem.Price"),
+			},
+			{
+				Name:        "tax_rates",
+				Description: "The tax rates which apply to the invoice item.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("InvoiceItem.TaxRates"),
+			},
+		}),
+	}
+}
+
+// invoiceItem is an invoice item along with whether it is pending, and its
+// version dependent fields.
+type invoiceItem struct {
+	InvoiceItem *stripe.InvoiceItem
+	Pending     bool
+	PriceID     string
+}
+
+// invoiceItemFields are the fields of an invoice item that depend on the API
+// version.
+type invoiceItemFields struct {
+	Parent  *invoiceParentFields `json:"parent"`
+	Pricing *pricingFields       `json:"pricing"`
+}
+
+func newInvoiceItem(item *stripe.InvoiceItem, f invoiceItemFields) *invoiceItem {
+	row := &invoiceItem{
+		InvoiceItem: item,
+		Pending:     item.Invoice == nil,
+	}
+	if item.Price != nil {
+		row.PriceID = item.Price.ID
+	}
+	setInvoiceItemVersionedFields(row, f)
+	return row
+}
+
+func listInvoiceItems(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_invoice_item.listInvoiceItems", "connection_error", err)
+		return nil, err
+	}
+	params := &stripe.InvoiceItemListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	q := d.EqualsQuals
+	if q["customer"] != nil {
+		params.Customer = stripe.String(q["customer"].GetStringValue())
+	}
+	if q["invoice"] != nil {
+		params.Invoice = stripe.String(q["invoice"].GetStringValue())
+	}
+
+	// Comparison values
+	quals := d.Quals
+
+	if quals["pending"] != nil {
+		for _, q := range quals["pending"].Quals {
+			switch q.Operator {
+			case "=":
+				params.Pending = stripe.Bool(q.Value.GetBoolValue())
+			case "<>":
+				params.Pending = stripe.Bool(!q.Value.GetBoolValue())
+			}
+		}
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	err = listQueryRanges(ctx, d, []string{"created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.Created, params.CreatedRange = r["created"].listParams()
+		var page rawPage[invoiceItemFields]
+		i := conn.InvoiceItems.List(&params)
+		for i.Next() {
+			item := i.InvoiceItem()
+			if !stream(newInvoiceItem(item, page.get(i.InvoiceItemList().LastResponse, item.ID))) {
+				break
+			}
+		}
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_invoice_item.listInvoiceItems", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getInvoiceItem(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_invoice_item.getInvoiceItem", "connection_error", err)
+		return nil, err
+	}
+	quals := d.EqualsQuals
+	id := quals["id"].GetStringValue()
+	params := &stripe.InvoiceItemParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.InvoiceItems.Get(id, params)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_invoice_item.getInvoiceItem", "query_error", err, "id", id)
+		return nil, err
+	}
+	return newInvoiceItem(item, decodeRawFields[invoiceItemFields](item.LastResponse)), nil
+}
+
+// setInvoiceItemVersionedFields sets the price and subscription of an invoice
+// item from its pricing and parent, for API versions that no longer return
+// them on the invoice item.
+func setInvoiceItemVersionedFields(i *invoiceItem, f invoiceItemFields) {
+	if i.PriceID == "" {
+		i.PriceID = f.Pricing.priceId()
+	}
+	if i.InvoiceItem.Subscription == nil {
+		if id := f.Parent.subscriptionId(); id != "" {
+			i.InvoiceItem.Subscription = &stripe.Subscription{ID: id}
+		}
+	}
+}
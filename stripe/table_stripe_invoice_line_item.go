@@ -0,0 +1,312 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripeInvoiceLineItem(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_invoice_line_item",
+		Description: "Line items of Stripe invoices, including all pages of invoices with many lines.",
+		List: &plugin.ListConfig{
+			Hydrate:    listInvoiceLineItems,
+			KeyColumns: plugin.SingleColumn("invoice_id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns:           commonColumns(invoiceLineItemColumns()),
+	}
+}
+
+// invoiceLineItemColumns returns the columns of invoice line items, for the
+// line items of both invoices and upcoming invoices.
+func invoiceLineItemColumns() []*plugin.Column {
+	return []*plugin.Column{
+		{
+			Name:        "id",
+			Description: "Unique identifier for the line item.",
+			Type:        proto.ColumnType_STRING,
+			Transform:   transform.FromField("InvoiceLineItem.ID"),
+		},
+		{
+			Name:        "invoice_id",
+			Description: "ID of the invoice the line item belongs to, or null for upcoming invoices without an ID.",
+			Type:        proto.ColumnType_STRING,
+			Transform:   transform.FromField("InvoiceID").NullIfZero(),
+		},
+		{
+			Name:        "amount",
+			Description: "The amount, in the smallest currency unit.",
+			Type:        proto.ColumnType_INT,
+			Transform:   transform.FromField("InvoiceLineItem.Amount"),
+		},
+		{
+			Name:        "amount_excluding_tax",
+			Description: "The amount in the smallest currency unit, excluding all tax and discounts.",
+			Type:        proto.ColumnType_INT,
+			Transform:   transform.FromField("InvoiceLineItem.AmountExcludingTax"),
+		},
+		{
+			Name:        "currency",
+			Description: "Three-letter ISO currency code, in lowercase.",
+			Type:        proto.ColumnType_STRING,
+			Transform:   transform.FromField("InvoiceLineItem.Currency"),
+		},
+		{
+			Name:        "description",
+			Description: "An arbitrary string attached to the line item. Often useful for displaying to users.",
+			Type:        proto.ColumnType_STRING,
+			Transform:   transform.FromField("InvoiceLineItem.Description"),
+		},
+		{
+			Name:        "discountable",
+			Description: "If true, discounts will apply to this line item. Always false for prorations.",
+			Type:        proto.ColumnType_BOOL,
+			Transform:   transform.FromField("InvoiceLineItem.Discountable"),
+		},
+		{
+			Name:        "invoice_item",
+			Description: "ID of the invoice item associated with this line item, if any.",
+			Type:        proto.ColumnType_STRING,
+			Transform:   transform.FromField("InvoiceLineItem.InvoiceItem.ID"),
+		},
+		{
+			Name:        "period_end",
+			Description: "End of the period the line item covers.",
+			Type:        proto.ColumnType_TIMESTAMP,
+			Transform:   transform.FromField("InvoiceLineItem.Period.End").Transform(transform.UnixToTimestamp),
+		},
+		{
+			Name:        "period_start",
+			Description: "Start of the period the line item covers.",
+			Type:        proto.ColumnType_TIMESTAMP,
+			Transform:   transform.FromField("InvoiceLineItem.Period.Start").Transform(transform.UnixToTimestamp),
+		},
+		{
+			Name:        "price_id",
+			Description: "ID of the price of the line item.",
+			Type:        proto.ColumnType_STRING,
+			Transform:   transform.FromField("PriceID"),
+		},
+		{
+			Name:        "proration",
+			Description: "Whether this is a proration.",
+			Type:        proto.ColumnType_BOOL,
+			Transform:   transform.FromField("Proration"),
+		},
+		{
+			Name:        "quantity",
+			Description: "The quantity of the subscription, if the line item is a subscription or a proration.",
+			Type:        proto.ColumnType_INT,
+			Transform:   transform.FromField("InvoiceLineItem.Quantity"),
+		},
+		{
+			Name:        "subscription",
+			Description: "ID of the subscription that the line item pertains to, if any.",
+			Type:        proto.ColumnType_STRING,
+			Transform:   transform.FromField("InvoiceLineItem.Subscription.ID"),
+		},
+		{
+			Name:        "subscription_item",
+			Description: "ID of the subscription item that generated this line item, if any.",
+			Type:        proto.ColumnType_STRING,
+			Transform:   transform.FromField("InvoiceLineItem.SubscriptionItem.ID"),
+		},
+		{
+			Name:        "type",
+			Description: "The type of the source of this line item, either invoiceitem or subscription.",
+			Type:        proto.ColumnType_STRING,
+			Transform:   transform.FromField("InvoiceLineItem.Type"),
+		},
+		{
+			Name:        "unit_amount_excluding_tax",
+			Description: "The unit amount of the line item in the smallest currency unit, excluding all tax and discounts.",
+			Type:        proto.ColumnType_DOUBLE,
+			Transform:   transform.FromField("InvoiceLineItem.UnitAmountExcludingTax"),
+		},
+
+		// JSON columns for complex data
+		{
+			Name:        "discount_amounts",
+			Description: "The amount of discount calculated per discount for this line item.",
+			Type:        proto.ColumnType_JSON,
+			Transform:   transform.FromField("InvoiceLineItem.DiscountAmounts"),
+		},
+		{
+			Name:        "discounts",
+			Description: "The discounts applied to the line item.",
+			Type:        proto.ColumnType_JSON,
+			Transform:   transform.FromField("InvoiceLineItem.Discounts"),
+		},
+		{
+			Name:        "metadata",
+			Description: "Set of key-value pairs attached to the line item. For subscription line items, this is the metadata of the subscription.",
+			Type:        proto.ColumnType_JSON,
+			Transform:   transform.FromField("InvoiceLineItem.Metadata"),
+		},
+		{
+			Name:        "plan",
+			Description: "The plan of the subscription, if the line item is a subscription or a proration.",
+			Type:        proto.ColumnType_JSON,
+			Transform:   transform.FromField("InvoiceLineItem.Plan"),
+		},
+		{
+			Name:        "price",
+			Description: "The price of the line item.",
+			Type:        proto.ColumnType_JSON,
+			Transform:   transform.FromField("InvoiceLineItem.Price"),
+		},
+		{
+			Name:        "proration_details",
+			Description: "Additional details for proration line items.",
+			Type:        proto.ColumnType_JSON,
+			Transform:   transform.FromField("InvoiceLineItem.ProrationDetails"),
+		},
+		{
+			Name:        "tax_amounts",
+			Description: "The amount of tax calculated per tax rate for this line item.",
+			Type:        proto.ColumnType_JSON,
+			Transform:   transform.FromField("TaxAmounts"),
+		},
+		{
+			Name:        "tax_rates",
+			Description: "The tax rates which apply to the line item.",
+			Type:        proto.ColumnType_JSON,
+			Transform:   transform.FromField("InvoiceLineItem.TaxRates"),
+		},
+	}
+}
+
+// invoiceLineItem is a line item along with the invoice it belongs to, which
+// line items do not return, and its version dependent fields.
+type invoiceLineItem struct {
+	InvoiceID       string
+	InvoiceLineItem *stripe.InvoiceLineItem
+	PriceID         string
+	Proration       bool
+	TaxAmounts      interface{}
+}
+
+// invoiceLineItemFields are the fields of an invoice line item that depend on
+// the API version.
+type invoiceLineItemFields struct {
+	Parent *struct {
+		InvoiceItemDetails *struct {
+			Proration bool `json:"proration"`
+		} `json:"invoice_item_details"`
+		SubscriptionItemDetails *struct {
+			Proration bool `json:"proration"`
+		} `json:"subscription_item_details"`
+	} `json:"parent"`
+	Pricing *pricingFields  `json:"pricing"`
+	Taxes   json.RawMessage `json:"taxes"`
+}
+
+// pricingFields is the pricing of an invoice line item or invoice item. From
+// the basil API version, it holds the price of the object.
+type pricingFields struct {
+	PriceDetails *struct {
+		Price string `json:"price"`
+	} `json:"price_details"`
+}
+
+// priceId returns the ID of the price in the pricing.
+func (p *pricingFields) priceId() string {
+	if p == nil || p.PriceDetails == nil {
+		return ""
+	}
+	return p.PriceDetails.Price
+}
+
+func newInvoiceLineItem(invoiceID string, item *stripe.InvoiceLineItem, f invoiceLineItemFields) *invoiceLineItem {
+	row := &invoiceLineItem{
+		InvoiceID:       invoiceID,
+		InvoiceLineItem: item,
+		Proration:       item.Proration,
+	}
+	if item.Price != nil {
+		row.PriceID = item.Price.ID
+	}
+	if item.TaxAmounts != nil {
+		row.TaxAmounts = item.TaxAmounts
+	}
+	setInvoiceLineItemVersionedFields(row, f)
+	return row
+}
+
+func listInvoiceLineItems(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_invoice_line_item.listInvoiceLineItems", "connection_error", err)
+		return nil, err
+	}
+
+	invoiceID := d.EqualsQuals["invoice_id"].GetStringValue()
+	if invoiceID == "" {
+		return nil, nil
+	}
+
+	params := &stripe.InvoiceListLinesParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+		Invoice: stripe.String(invoiceID),
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	var page rawPage[invoiceLineItemFields]
+	var count int64
+	i := conn.Invoices.ListLines(params)
+	for i.Next() {
+		item := i.InvoiceLineItem()
+		d.StreamListItem(ctx, newInvoiceLineItem(invoiceID, item, page.get(i.InvoiceLineItemList().LastResponse, item.ID)))
+		count++
+		if limit != nil {
+			if count >= *limit {
+				break
+			}
+		}
+	}
+	if err := i.Err(); err != nil {
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		plugin.Logger(ctx).Error("stripe_invoice_line_item.listInvoiceLineItems", "query_error", err, "params", params, "i", i)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// setInvoiceLineItemVersionedFields sets the price, proration and taxes of an
+// invoice line item from its pricing, parent and taxes, for API versions that
+// no longer return them on the line item.
+func setInvoiceLineItemVersionedFields(i *invoiceLineItem, f invoiceLineItemFields) {
+	if i.PriceID == "" {
+		i.PriceID = f.Pricing.priceId()
+	}
+	if f.Parent != nil {
+		if f.Parent.InvoiceItemDetails != nil {
+			i.Proration = i.Proration || f.Parent.InvoiceItemDetails.Proration
+		}
+		if f.Parent.SubscriptionItemDetails != nil {
+			i.Proration = i.Proration || f.Parent.SubscriptionItemDetails.Proration
+		}
+	}
+	if i.TaxAmounts == nil {
+		i.TaxAmounts = rawValue(f.Taxes)
+	}
+}
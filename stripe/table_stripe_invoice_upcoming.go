@@ -0,0 +1,83 @@
+package stripe
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/client"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripeInvoiceUpcoming(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_invoice_upcoming",
+		Description: "Preview of the next invoice of a customer or subscription, with all pending invoice items and prorations.",
+		List: &plugin.ListConfig{
+			Hydrate:    listInvoiceUpcoming,
+			KeyColumns: plugin.AnyColumn([]string{"customer_id", "subscription_id"}),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns(append(invoiceColumns(),
+			&plugin.Column{Name: "customer_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("Customer.ID"), Description: "ID of the customer who will be billed."},
+		)),
+	}
+}
+
+func listInvoiceUpcoming(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_invoice_upcoming.listInvoiceUpcoming", "connection_error", err)
+		return nil, err
+	}
+
+	item, err := upcomingInvoice(ctx, d, conn)
+	if err != nil {
+		if isNoUpcomingInvoiceError(err) || isNotFoundError(err) {
+			return nil, nil
+		}
+		plugin.Logger(ctx).Error("stripe_invoice_upcoming.listInvoiceUpcoming", "query_error", err)
+		return nil, err
+	}
+	d.StreamListItem(ctx, item)
+
+	return nil, nil
+}
+
+// upcomingInvoice previews the next invoice of the customer or subscription
+// in the quals.
+func upcomingInvoice(ctx context.Context, d *plugin.QueryData, conn *client.API) (*stripe.Invoice, error) {
+	params := &stripe.InvoiceUpcomingParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	q := d.EqualsQuals
+	if q["customer_id"] != nil {
+		params.Customer = stripe.String(q["customer_id"].GetStringValue())
+	}
+	if q["subscription_id"] != nil {
+		params.Subscription = stripe.String(q["subscription_id"].GetStringValue())
+	}
+
+	var item *stripe.Invoice
+	var err error
+	if apiVersionAtLeast(d, apiVersionBasil) {
+		// The basil API version replaced the upcoming invoice endpoint with
+		// create_preview, which stripe-go has no method for in this version
+		item = &stripe.Invoice{}
+		err = conn.Invoices.B.Call(http.MethodPost, "/v1/invoices/create_preview", conn.Invoices.Key, params, item)
+	} else {
+		item, err = conn.Invoices.Upcoming(params)
+	}
+	if err != nil {
+		return nil, err
+	}
+	setInvoiceVersionedFields(item, decodeRawFields[invoiceFields](item.LastResponse))
+	return item, nil
+}
@@ -0,0 +1,110 @@
+package stripe
+
+import (
+	"context"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/invoice"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripeInvoiceUpcomingLineItem(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_invoice_upcoming_line_item",
+		Description: "Line items of the upcoming invoice of a customer or subscription, including all pages of invoices with many lines.",
+		List: &plugin.ListConfig{
+			Hydrate:    listInvoiceUpcomingLineItems,
+			KeyColumns: plugin.AnyColumn([]string{"customer_id", "subscription_id"}),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns(append(invoiceLineItemColumns(),
+			&plugin.Column{
+				Name:        "customer_id",
+				Description: "ID of the customer the upcoming invoice is previewed for. Only populated when the query specifies a customer.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("customer_id"),
+			},
+			&plugin.Column{
+				Name:        "subscription_id",
+				Description: "ID of the subscription the upcoming invoice is previewed for. Only populated when the query specifies a subscription.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("subscription_id"),
+			},
+		)),
+	}
+}
+
+func listInvoiceUpcomingLineItems(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_invoice_upcoming_line_item.listInvoiceUpcomingLineItems", "connection_error", err)
+		return nil, err
+	}
+
+	listParams := stripe.ListParams{
+		Context:       ctx,
+		Limit:         stripe.Int64(100),
+		StripeAccount: connectedAccount(ctx),
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *listParams.Limit {
+			listParams.Limit = limit
+		}
+	}
+
+	var invoiceID string
+	var i *invoice.LineItemIter
+	if apiVersionAtLeast(d, apiVersionBasil) {
+		// The basil API version no longer lists the lines of upcoming invoices,
+		// so they are listed from the invoice preview instead
+		item, err := upcomingInvoice(ctx, d, conn)
+		if err != nil {
+			if isNoUpcomingInvoiceError(err) || isNotFoundError(err) {
+				return nil, nil
+			}
+			plugin.Logger(ctx).Error("stripe_invoice_upcoming_line_item.listInvoiceUpcomingLineItems", "query_error", err)
+			return nil, err
+		}
+		invoiceID = item.ID
+		i = conn.Invoices.ListLines(&stripe.InvoiceListLinesParams{
+			ListParams: listParams,
+			Invoice:    stripe.String(invoiceID),
+		})
+	} else {
+		params := &stripe.InvoiceUpcomingLinesParams{ListParams: listParams}
+		q := d.EqualsQuals
+		if q["customer_id"] != nil {
+			params.Customer = stripe.String(q["customer_id"].GetStringValue())
+		}
+		if q["subscription_id"] != nil {
+			params.Subscription = stripe.String(q["subscription_id"].GetStringValue())
+		}
+		i = conn.Invoices.UpcomingLines(params)
+	}
+
+	var page rawPage[invoiceLineItemFields]
+	var count int64
+	for i.Next() {
+		item := i.InvoiceLineItem()
+		d.StreamListItem(ctx, newInvoiceLineItem(invoiceID, item, page.get(i.InvoiceLineItemList().LastResponse, item.ID)))
+		count++
+		if limit != nil {
+			if count >= *limit {
+				break
+			}
+		}
+	}
+	if err := i.Err(); err != nil {
+		if isNoUpcomingInvoiceError(err) || isNotFoundError(err) {
+			return nil, nil
+		}
+		plugin.Logger(ctx).Error("stripe_invoice_upcoming_line_item.listInvoiceUpcomingLineItems", "query_error", err)
+		return nil, err
+	}
+
+	return nil, nil
+}
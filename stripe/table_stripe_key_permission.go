@@ -0,0 +1,312 @@
+package stripe
+
+import (
+	"context"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/client"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripeKeyPermission(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_key_permission",
+		Description: "Whether the API key can read the resource behind each table of the plugin.",
+		List: &plugin.ListConfig{
+			Hydrate: listKeyPermissions,
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			{
+				Name:        "table_name",
+				Description: "The name of the table.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "endpoint",
+				Description: "The API endpoint probed to check whether the key can read the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			// The transform is explicit, so that false is never turned into null by
+			// the default transform
+			{
+				Name:        "can_read",
+				Description: "True if the API key can read the resource.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("CanRead"),
+			},
+			{
+				Name:        "error",
+				Description: "The error Stripe returned when the API key cannot read the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+		}),
+	}
+}
+
+type keyPermission struct {
+	TableName string
+	Endpoint  string
+	CanRead   bool
+	Error     string
+}
+
+// keyPermissionProbe requests a single object of the resource behind a
+// table, to check whether the API key can read it.
+type keyPermissionProbe struct {
+	TableName string
+	Endpoint  string
+	Probe     func(conn *client.API, params stripe.ListParams) error
+}
+
+// keyPermissionProbes holds a probe for each table of the plugin.
+var keyPermissionProbes = []keyPermissionProbe{
+	{
+		TableName: "stripe_account",
+		Endpoint:  "/v1/account",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			var err error
+			if params.StripeAccount != nil {
+				_, err = conn.Accounts.GetByID(*params.StripeAccount, &stripe.AccountParams{Params: stripe.Params{Context: params.Context}})
+			} else {
+				_, err = conn.Accounts.Get()
+			}
+			return err
+		},
+	},
+	{
+		TableName: "stripe_balance_transaction",
+		Endpoint:  "/v1/balance_transactions",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.BalanceTransactions.List(&stripe.BalanceTransactionListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_charge",
+		Endpoint:  "/v1/charges",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Charges.List(&stripe.ChargeListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_checkout_session",
+		Endpoint:  "/v1/checkout/sessions",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.CheckoutSessions.List(&stripe.CheckoutSessionListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		// Listing Checkout Session line items requires a session, and reading
+		// them requires the same permission as reading sessions
+		TableName: "stripe_checkout_session_line_item",
+		Endpoint:  "/v1/checkout/sessions",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.CheckoutSessions.List(&stripe.CheckoutSessionListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_coupon",
+		Endpoint:  "/v1/coupons",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Coupons.List(&stripe.CouponListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_credit_note",
+		Endpoint:  "/v1/credit_notes",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.CreditNotes.List(&stripe.CreditNoteListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		// Listing credit note line items requires a credit note, and reading
+		// them requires the same permission as reading credit notes
+		TableName: "stripe_credit_note_line_item",
+		Endpoint:  "/v1/credit_notes",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.CreditNotes.List(&stripe.CreditNoteListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_customer",
+		Endpoint:  "/v1/customers",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Customers.List(&stripe.CustomerListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_dispute",
+		Endpoint:  "/v1/disputes",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Disputes.List(&stripe.DisputeListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_event",
+		Endpoint:  "/v1/events",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Events.List(&stripe.EventListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_invoice",
+		Endpoint:  "/v1/invoices",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Invoices.List(&stripe.InvoiceListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_invoice_item",
+		Endpoint:  "/v1/invoiceitems",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.InvoiceItems.List(&stripe.InvoiceItemListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		// Listing invoice line items requires an invoice, and reading them
+		// requires the same permission as reading invoices
+		TableName: "stripe_invoice_line_item",
+		Endpoint:  "/v1/invoices",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Invoices.List(&stripe.InvoiceListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		// Previewing an upcoming invoice requires a customer or subscription,
+		// and requires the same permission as reading invoices
+		TableName: "stripe_invoice_upcoming",
+		Endpoint:  "/v1/invoices",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Invoices.List(&stripe.InvoiceListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		// Listing the line items of an upcoming invoice requires a customer or
+		// subscription, and requires the same permission as reading invoices
+		TableName: "stripe_invoice_upcoming_line_item",
+		Endpoint:  "/v1/invoices",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Invoices.List(&stripe.InvoiceListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_payment_intent",
+		Endpoint:  "/v1/payment_intents",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.PaymentIntents.List(&stripe.PaymentIntentListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_payment_link",
+		Endpoint:  "/v1/payment_links",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.PaymentLinks.List(&stripe.PaymentLinkListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_payout",
+		Endpoint:  "/v1/payouts",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Payouts.List(&stripe.PayoutListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_plan",
+		Endpoint:  "/v1/plans",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Plans.List(&stripe.PlanListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_price",
+		Endpoint:  "/v1/prices",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Prices.List(&stripe.PriceListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_product",
+		Endpoint:  "/v1/products",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Products.List(&stripe.ProductListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_promotion_code",
+		Endpoint:  "/v1/promotion_codes",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.PromotionCodes.List(&stripe.PromotionCodeListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_refund",
+		Endpoint:  "/v1/refunds",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Refunds.List(&stripe.RefundListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_subscription",
+		Endpoint:  "/v1/subscriptions",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Subscriptions.List(&stripe.SubscriptionListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		// Listing subscription items requires a subscription, and reading them
+		// requires the same permission as reading subscriptions
+		TableName: "stripe_subscription_item",
+		Endpoint:  "/v1/subscriptions",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.Subscriptions.List(&stripe.SubscriptionListParams{ListParams: params}).Iter)
+		},
+	},
+	{
+		TableName: "stripe_webhook_endpoint",
+		Endpoint:  "/v1/webhook_endpoints",
+		Probe: func(conn *client.API, params stripe.ListParams) error {
+			return probeList(conn.WebhookEndpoints.List(&stripe.WebhookEndpointListParams{ListParams: params}).Iter)
+		},
+	},
+}
+
+// probeList returns the error of the first page of a list.
+func probeList(i *stripe.Iter) error {
+	i.Next()
+	return i.Err()
+}
+
+func listKeyPermissions(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_key_permission.listKeyPermissions", "connection_error", err)
+		return nil, err
+	}
+
+	params := stripe.ListParams{
+		Context:       ctx,
+		Limit:         stripe.Int64(1),
+		StripeAccount: connectedAccount(ctx),
+	}
+
+	for _, p := range keyPermissionProbes {
+		item := keyPermission{
+			TableName: p.TableName,
+			Endpoint:  p.Endpoint,
+			CanRead:   true,
+		}
+		if err := p.Probe(conn, params); err != nil {
+			if !isPermissionError(err) {
+				plugin.Logger(ctx).Error("stripe_key_permission.listKeyPermissions", "query_error", err, "table_name", p.TableName)
+				return nil, err
+			}
+			item.CanRead = false
+			item.Error = err.(*stripe.Error).Msg
+		}
+		d.StreamListItem(ctx, item)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,281 @@
+package stripe
+
+import (
+	"context"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripePaymentIntent(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_payment_intent",
+		Description: "Payment intents guide you through the process of collecting a payment from your customer.",
+		List: &plugin.ListConfig{
+			Hydrate: listPaymentIntents,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "created", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
+				{Name: "customer", Require: plugin.Optional, Operators: []string{"="}},
+			},
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getPaymentIntent,
+			KeyColumns: plugin.SingleColumn("id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			// Basic fields
+			{
+				Name:        "id",
+				Description: "Unique identifier for the payment intent.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "amount",
+				Description: "Amount intended to be collected by this payment intent, in the smallest currency unit.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "amount_capturable",
+				Description: "Amount that can be captured from this payment intent.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "amount_received",
+				Description: "Amount that this payment intent collected.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "application_fee_amount",
+				Description: "The amount of the application fee (if any) that will be requested to be applied to the payment and transferred to the application owner's Stripe account.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "canceled_at",
+				Description: "Time at which the payment intent was canceled.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("CanceledAt").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "cancellation_reason",
+				Description: "Reason for cancellation of this payment intent, either user-provided or generated by Stripe internally.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "capture_method",
+				Description: "Controls when the funds will be captured from the customer's account.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "confirmation_method",
+				Description: "Describes whether we can confirm this payment intent automatically, or if it requires customer action to confirm the payment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "created",
+				Description: "Timestamp when the payment intent was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Created").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "currency",
+				Description: "Currency of the payment intent.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "customer",
+				Description: "ID of the customer this payment intent belongs to, if one exists.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Customer.ID"),
+			},
+			{
+				Name:        "description",
+				Description: "Description of the payment intent.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "invoice",
+				Description: "ID of the invoice that created this payment intent, if it exists.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Invoice.ID"),
+			},
+			{
+				Name:        "latest_charge",
+				Description: "ID of the latest charge created by this payment intent.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("LatestCharge.ID"),
+			},
+			{
+				Name:        "payment_method",
+				Description: "ID of the payment method used in this payment intent.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PaymentMethod.ID"),
+			},
+			{
+				Name:        "receipt_email",
+				Description: "Email address that the receipt for the resulting payment will be sent to.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "setup_future_usage",
+				Description: "Indicates that you intend to make future payments with this payment intent's payment method.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "statement_descriptor",
+				Description: "Text that appears on the customer's statement as the statement descriptor for a non-card charge.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "status",
+				Description: "Status of the payment intent (e.g., requires_payment_method, requires_confirmation, requires_action, processing, requires_capture, canceled, succeeded).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "transfer_group",
+				Description: "A string that identifies the resulting payment as part of a group.",
+				Type:        proto.ColumnType_STRING,
+			},
+
+			// JSON columns for complex data
+			{
+				Name:        "amount_details",
+				Description: "Details about the amounts of the payment intent, such as tips.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "application",
+				Description: "Application that created the payment intent.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "automatic_payment_methods",
+				Description: "Settings to configure compatible payment methods from the Stripe Dashboard.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "last_payment_error",
+				Description: "The payment error encountered in the previous payment intent confirmation.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "metadata",
+				Description: "Metadata associated with the payment intent.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "next_action",
+				Description: "If present, this property tells you what actions you need to take in order for your customer to fulfill a payment using the provided source.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "on_behalf_of",
+				Description: "The account (if any) for which the funds of the payment intent are intended.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "payment_method_options",
+				Description: "Payment-method-specific configuration for this payment intent.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "payment_method_types",
+				Description: "The list of payment method types that this payment intent is allowed to use.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "processing",
+				Description: "If present, this property tells you about the processing state of the payment.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "review",
+				Description: "Review associated with the payment intent, if it was flagged by Radar.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "shipping",
+				Description: "Shipping information for the payment intent.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "transfer_data",
+				Description: "The data that automatically creates a transfer after the payment finalizes.",
+				Type:        proto.ColumnType_JSON,
+			},
+		}),
+	}
+}
+
+func listPaymentIntents(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_payment_intent.listPaymentIntents", "connection_error", err)
+		return nil, err
+	}
+	params := &stripe.PaymentIntentListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	q := d.EqualsQuals
+	if q["customer"] != nil {
+		params.Customer = stripe.String(q["customer"].GetStringValue())
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	err = listQueryRanges(ctx, d, []string{"created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.Created, params.CreatedRange = r["created"].listParams()
+		i := conn.PaymentIntents.List(&params)
+		for i.Next() {
+			if !stream(i.PaymentIntent()) {
+				break
+			}
+		}
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_payment_intent.listPaymentIntents", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getPaymentIntent(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_payment_intent.getPaymentIntent", "connection_error", err)
+		return nil, err
+	}
+	quals := d.EqualsQuals
+	id := quals["id"].GetStringValue()
+	params := &stripe.PaymentIntentParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.PaymentIntents.Get(id, params)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_payment_intent.getPaymentIntent", "query_error", err, "id", id)
+		return nil, err
+	}
+	return item, nil
+}
@@ -0,0 +1,283 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripePaymentLink(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_payment_link",
+		Description: "Payment Links are shareable URLs to a Checkout page for a fixed set of items.",
+		List: &plugin.ListConfig{
+			Hydrate: listPaymentLinks,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "active", Operators: []string{"=", "<>"}, Require: plugin.Optional},
+			},
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getPaymentLink,
+			KeyColumns: plugin.SingleColumn("id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			// Basic fields
+			{
+				Name:        "id",
+				Description: "Unique identifier for the Payment Link.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PaymentLink.ID"),
+			},
+			{
+				Name:        "url",
+				Description: "The public URL that can be shared with customers.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PaymentLink.URL"),
+			},
+			{
+				Name:        "active",
+				Description: "Whether the Payment Link is active. Customers cannot pay through inactive links.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("PaymentLink.Active"),
+			},
+			{
+				Name:        "allow_promotion_codes",
+				Description: "Whether user redeemable promotion codes are enabled.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("PaymentLink.AllowPromotionCodes"),
+			},
+			{
+				Name:        "application",
+				Description: "The ID of the Connect application that created the Payment Link.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PaymentLink.Application.ID"),
+			},
+			{
+				Name:        "application_fee_amount",
+				Description: "The amount of the application fee, if any, that will be requested to be applied to the payment and transferred to the application owner's Stripe account.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("PaymentLink.ApplicationFeeAmount"),
+			},
+			{
+				Name:        "application_fee_percent",
+				Description: "The percentage of the subscription invoice total that will be transferred to the application owner's Stripe account.",
+				Type:        proto.ColumnType_DOUBLE,
+				Transform:   transform.FromField("PaymentLink.ApplicationFeePercent"),
+			},
+			{
+				Name:        "billing_address_collection",
+				Description: "Whether Checkout collects the customer's billing address, either auto or required.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PaymentLink.BillingAddressCollection"),
+			},
+			{
+				Name:        "currency",
+				Description: "Three-letter ISO currency code, in lowercase.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PaymentLink.Currency"),
+			},
+			{
+				Name:        "customer_creation",
+				Description: "Whether a customer is created when the Checkout Session completes, either always or if_required.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PaymentLink.CustomerCreation"),
+			},
+			{
+				Name:        "on_behalf_of",
+				Description: "The account on behalf of which to charge.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PaymentLink.OnBehalfOf.ID"),
+			},
+			{
+				Name:        "payment_method_collection",
+				Description: "Whether Checkout collects a payment method, either always or if_required.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PaymentLink.PaymentMethodCollection"),
+			},
+			{
+				Name:        "submit_type",
+				Description: "The type of transaction being performed, which customizes the text on the pay button.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PaymentLink.SubmitType"),
+			},
+
+			// JSON columns for complex data
+			{
+				Name:        "after_completion",
+				Description: "The behavior after the purchase is complete, either a confirmation page or a redirect.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("PaymentLink.AfterCompletion"),
+			},
+			{
+				Name:        "custom_fields",
+				Description: "The custom fields collected from the customer.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("PaymentLink.CustomFields"),
+			},
+			{
+				Name:        "line_items",
+				Description: "The line items of the Payment Link, with the prices behind them.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     listPaymentLinkLineItems,
+				Transform:   transform.FromValue(),
+			},
+			{
+				Name:        "metadata",
+				Description: "Set of key-value pairs attached to the Payment Link.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("PaymentLink.Metadata"),
+			},
+			{
+				Name:        "payment_method_types",
+				Description: "The payment method types the customer can use. If empty, payment methods are chosen from the account settings.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("PaymentLink.PaymentMethodTypes"),
+			},
+			{
+				Name:        "restrictions",
+				Description: "The settings that restrict the usage of the Payment Link, such as the number of completed sessions after which it is deactivated.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Restrictions"),
+			},
+			{
+				Name:        "subscription_data",
+				Description: "The settings of the subscriptions created by the Payment Link, when it has recurring prices.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("PaymentLink.SubscriptionData"),
+			},
+		}),
+	}
+}
+
+// paymentLink is a Payment Link along with the fields the stripe-go version
+// does not decode.
+type paymentLink struct {
+	PaymentLink  *stripe.PaymentLink
+	Restrictions interface{}
+}
+
+// paymentLinkFields are the fields of a Payment Link that the stripe-go
+// version does not decode.
+type paymentLinkFields struct {
+	Restrictions json.RawMessage `json:"restrictions"`
+}
+
+func newPaymentLink(item *stripe.PaymentLink, f paymentLinkFields) *paymentLink {
+	return &paymentLink{
+		PaymentLink:  item,
+		Restrictions: rawValue(f.Restrictions),
+	}
+}
+
+func listPaymentLinks(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_payment_link.listPaymentLinks", "connection_error", err)
+		return nil, err
+	}
+	params := &stripe.PaymentLinkListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	// Comparison values
+	quals := d.Quals
+
+	if quals["active"] != nil {
+		for _, q := range quals["active"].Quals {
+			switch q.Operator {
+			case "=":
+				params.Active = stripe.Bool(q.Value.GetBoolValue())
+			case "<>":
+				params.Active = stripe.Bool(!q.Value.GetBoolValue())
+			}
+		}
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	var page rawPage[paymentLinkFields]
+	var count int64
+	i := conn.PaymentLinks.List(params)
+	for i.Next() {
+		item := i.PaymentLink()
+		d.StreamListItem(ctx, newPaymentLink(item, page.get(i.PaymentLinkList().LastResponse, item.ID)))
+		count++
+		if limit != nil {
+			if count >= *limit {
+				break
+			}
+		}
+	}
+	if err := i.Err(); err != nil {
+		plugin.Logger(ctx).Error("stripe_payment_link.listPaymentLinks", "query_error", err, "params", params, "i", i)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getPaymentLink(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_payment_link.getPaymentLink", "connection_error", err)
+		return nil, err
+	}
+	quals := d.EqualsQuals
+	id := quals["id"].GetStringValue()
+	params := &stripe.PaymentLinkParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.PaymentLinks.Get(id, params)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_payment_link.getPaymentLink", "query_error", err, "id", id)
+		return nil, err
+	}
+	return newPaymentLink(item, decodeRawFields[paymentLinkFields](item.LastResponse)), nil
+}
+
+// listPaymentLinkLineItems lists all the line items of a Payment Link, which
+// include their prices.
+func listPaymentLinkLineItems(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_payment_link.listPaymentLinkLineItems", "connection_error", err)
+		return nil, err
+	}
+	id := h.Item.(*paymentLink).PaymentLink.ID
+	params := &stripe.PaymentLinkListLineItemsParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+		PaymentLink: stripe.String(id),
+	}
+
+	var items []*stripe.LineItem
+	i := conn.PaymentLinks.ListLineItems(params)
+	for i.Next() {
+		items = append(items, i.LineItem())
+	}
+	if err := i.Err(); err != nil {
+		plugin.Logger(ctx).Error("stripe_payment_link.listPaymentLinkLineItems", "query_error", err, "id", id)
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,267 @@
+package stripe
+
+import (
+	"context"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripePayout(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_payout",
+		Description: "Payouts of funds from your Stripe balance to your bank account or debit card.",
+		List: &plugin.ListConfig{
+			Hydrate: listPayouts,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "arrival_date", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
+				{Name: "created", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
+				{Name: "destination", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "status", Require: plugin.Optional, Operators: []string{"="}},
+			},
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getPayout,
+			KeyColumns: plugin.SingleColumn("id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			// Basic fields
+			{
+				Name:        "id",
+				Description: "Unique identifier for the payout.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "amount",
+				Description: "Amount to be transferred to your bank account or debit card, in the smallest currency unit.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "arrival_date",
+				Description: "Date the payout is expected to arrive in the bank.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("ArrivalDate").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "automatic",
+				Description: "Returns true if the payout was created by an automated payout schedule, and false if it was requested manually.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "balance_transaction",
+				Description: "ID of the balance transaction that describes the impact of this payout on your account balance.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("BalanceTransaction.ID"),
+			},
+			{
+				Name:        "created",
+				Description: "Timestamp when the payout was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Created").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "currency",
+				Description: "Currency of the payout.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "description",
+				Description: "An arbitrary string attached to the object. Often useful for displaying to users.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "destination",
+				Description: "ID of the bank account or card the payout was sent to.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Destination.ID"),
+			},
+			{
+				Name:        "failure_balance_transaction",
+				Description: "If the payout failed or was canceled, the ID of the balance transaction that describes the reversal of the payout.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("FailureBalanceTransaction.ID"),
+			},
+			{
+				Name:        "failure_code",
+				Description: "Error code explaining the reason for payout failure, if available.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "failure_message",
+				Description: "Message to user further explaining the reason for payout failure, if available.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "method",
+				Description: "The method used to send this payout, either standard or instant.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "original_payout",
+				Description: "If the payout reverses another, the ID of the original payout.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("OriginalPayout.ID"),
+			},
+			{
+				Name:        "reconciliation_status",
+				Description: "If completed, the balance transactions API may be used to list all balance transactions paid out in this payout.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "reversed_by",
+				Description: "If the payout was reversed, the ID of the payout that reverses it.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ReversedBy.ID"),
+			},
+			{
+				Name:        "source_type",
+				Description: "The source balance this payout came from (e.g., card, fpx, bank_account).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "statement_descriptor",
+				Description: "Extra information about a payout that displays on the user's bank statement.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "status",
+				Description: "Current status of the payout (e.g., paid, pending, in_transit, canceled, failed).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "type",
+				Description: "Can be bank_account or card.",
+				Type:        proto.ColumnType_STRING,
+			},
+
+			// JSON columns for complex data
+			{
+				Name:        "balance_transactions",
+				Description: "Balance transactions settled in this payout. Only available for automatic payouts.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     listPayoutBalanceTransactions,
+				Transform:   transform.FromValue(),
+			},
+			{
+				Name:        "metadata",
+				Description: "Metadata associated with the payout.",
+				Type:        proto.ColumnType_JSON,
+			},
+		}),
+	}
+}
+
+func listPayouts(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_payout.listPayouts", "connection_error", err)
+		return nil, err
+	}
+	params := &stripe.PayoutListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	q := d.EqualsQuals
+	if q["destination"] != nil {
+		params.Destination = stripe.String(q["destination"].GetStringValue())
+	}
+	if q["status"] != nil {
+		params.Status = stripe.String(q["status"].GetStringValue())
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	err = listQueryRanges(ctx, d, []string{"arrival_date", "created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.ArrivalDate, params.ArrivalDateRange = r["arrival_date"].listParams()
+		params.Created, params.CreatedRange = r["created"].listParams()
+		i := conn.Payouts.List(&params)
+		for i.Next() {
+			if !stream(i.Payout()) {
+				break
+			}
+		}
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_payout.listPayouts", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getPayout(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_payout.getPayout", "connection_error", err)
+		return nil, err
+	}
+	quals := d.EqualsQuals
+	id := quals["id"].GetStringValue()
+	params := &stripe.PayoutParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.Payouts.Get(id, params)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_payout.getPayout", "query_error", err, "id", id)
+		return nil, err
+	}
+	return item, nil
+}
+
+// listPayoutBalanceTransactions lists the balance transactions settled in a payout
+func listPayoutBalanceTransactions(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	payout := h.Item.(*stripe.Payout)
+
+	// Stripe only tracks the balance transactions of automatic payouts
+	if !payout.Automatic {
+		return nil, nil
+	}
+
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_payout.listPayoutBalanceTransactions", "connection_error", err)
+		return nil, err
+	}
+
+	params := &stripe.BalanceTransactionListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+		Payout: stripe.String(payout.ID),
+	}
+
+	var transactions []*stripe.BalanceTransaction
+	i := conn.BalanceTransactions.List(params)
+	for i.Next() {
+		transactions = append(transactions, i.BalanceTransaction())
+	}
+	if err := i.Err(); err != nil {
+		plugin.Logger(ctx).Error("stripe_payout.listPayoutBalanceTransactions", "query_error", err, "id", payout.ID)
+		return nil, err
+	}
+
+	return transactions, nil
+}
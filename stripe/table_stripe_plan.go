@@ -25,6 +25,7 @@ func tableStripePlan(ctx context.Context) *plugin.Table {
 			Hydrate:    getPlan,
 			KeyColumns: plugin.SingleColumn("id"),
 		},
+		GetMatrixItemFunc: connectedAccountMatrix,
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
 			{Name: "id", Type: proto.ColumnType_STRING, Description: "Unique identifier for the plan."},
@@ -40,7 +41,6 @@ func tableStripePlan(ctx context.Context) *plugin.Table {
 			{Name: "deleted", Type: proto.ColumnType_BOOL, Description: "True if the plan is marked as deleted."},
 			{Name: "interval", Type: proto.ColumnType_STRING, Description: "The frequency at which a subscription is billed. One of day, week, month or year."},
 			{Name: "interval_count", Type: proto.ColumnType_INT, Transform: transform.FromField("IntervalCount"), Description: "The number of intervals (specified in the interval attribute) between subscription billings. For example, interval=month and interval_count=3 bills every 3 months."},
-			{Name: "livemode", Type: proto.ColumnType_BOOL, Description: "Has the value true if the plan exists in live mode or the value false if the plan exists in test mode."},
 			{Name: "metadata", Type: proto.ColumnType_JSON, Description: "Set of key-value pairs that you can attach to an plan. This can be useful for storing additional information about the plan in a structured format."},
 			{Name: "product_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("Product.ID"), Description: "ID of the product whose pricing this plan determines."},
 			{Name: "tiers", Type: proto.ColumnType_JSON, Description: "Each element represents a pricing tier. This parameter requires billing_scheme to be set to tiered."},
@@ -60,8 +60,9 @@ func listPlan(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (
 	}
 	params := &stripe.PlanListParams{
 		ListParams: stripe.ListParams{
-			Context: ctx,
-			Limit:   stripe.Int64(100),
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
 		},
 	}
 
@@ -84,36 +85,6 @@ func listPlan(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (
 		}
 	}
 
-	if quals["created"] != nil {
-		for _, q := range quals["created"].Quals {
-			tsSecs := q.Value.GetTimestampValue().GetSeconds()
-			switch q.Operator {
-			case ">":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.GreaterThan = tsSecs
-			case ">=":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.GreaterThanOrEqual = tsSecs
-			case "=":
-				params.Created = stripe.Int64(tsSecs)
-			case "<=":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.LesserThanOrEqual = tsSecs
-			case "<":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.LesserThan = tsSecs
-			}
-		}
-	}
-
 	limit := d.QueryContext.Limit
 	if d.QueryContext.Limit != nil {
 		if *limit < *params.ListParams.Limit {
@@ -121,19 +92,23 @@ func listPlan(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (
 		}
 	}
 
-	var count int64
-	i := conn.Plans.List(params)
-	for i.Next() {
-		d.StreamListItem(ctx, i.Plan())
-		count++
-		if limit != nil {
-			if count >= *limit {
+	err = listQueryRanges(ctx, d, []string{"created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.Created, params.CreatedRange = r["created"].listParams()
+		i := conn.Plans.List(&params)
+		for i.Next() {
+			if !stream(i.Plan()) {
 				break
 			}
 		}
-	}
-	if err := i.Err(); err != nil {
-		plugin.Logger(ctx).Error("stripe_plan.listPlan", "query_error", err, "params", params, "i", i)
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_plan.listPlan", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -148,7 +123,13 @@ func getPlan(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (i
 	}
 	quals := d.EqualsQuals
 	id := quals["id"].GetStringValue()
-	item, err := conn.Plans.Get(id, &stripe.PlanParams{})
+	params := &stripe.PlanParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.Plans.Get(id, params)
 	if err != nil {
 		plugin.Logger(ctx).Error("stripe_plan.getPlan", "query_error", err, "id", id)
 		return nil, err
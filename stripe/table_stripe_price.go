@@ -0,0 +1,166 @@
+package stripe
+
+import (
+	"context"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripePrice(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_price",
+		Description: "Prices define the unit cost, currency, and (optional) billing cycle for both recurring and one-time purchases of products.",
+		List: &plugin.ListConfig{
+			Hydrate: listPrice,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "active", Operators: []string{"=", "<>"}, Require: plugin.Optional},
+				{Name: "created", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
+				{Name: "currency", Require: plugin.Optional},
+				{Name: "lookup_key", Require: plugin.Optional},
+				{Name: "product_id", Require: plugin.Optional},
+				{Name: "recurring_interval", Require: plugin.Optional},
+				{Name: "recurring_usage_type", Require: plugin.Optional},
+				{Name: "type", Require: plugin.Optional},
+			},
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getPrice,
+			KeyColumns: plugin.SingleColumn("id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			// Top columns
+			{Name: "id", Type: proto.ColumnType_STRING, Description: "Unique identifier for the price."},
+			{Name: "nickname", Type: proto.ColumnType_STRING, Description: "A brief description of the price, hidden from customers."},
+			// Other columns
+			{Name: "active", Type: proto.ColumnType_BOOL, Description: "Whether the price can be used for new purchases."},
+			{Name: "billing_scheme", Type: proto.ColumnType_STRING, Description: "Describes how to compute the price per period. Either per_unit or tiered. per_unit indicates that the fixed amount (specified in unit_amount or unit_amount_decimal) will be charged per unit in quantity (for prices with usage_type=licensed), or per unit of total usage (for prices with usage_type=metered). tiered indicates that the unit pricing will be computed using a tiering strategy as defined using the tiers and tiers_mode attributes."},
+			{Name: "created", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("Created").Transform(transform.UnixToTimestamp), Description: "Time at which the price was created."},
+			{Name: "currency", Type: proto.ColumnType_STRING, Description: "Three-letter ISO currency code, in lowercase. Must be a supported currency."},
+			{Name: "currency_options", Type: proto.ColumnType_JSON, Description: "Prices defined in each available currency option. Each key must be a three-letter ISO currency code and a supported currency."},
+			{Name: "custom_unit_amount", Type: proto.ColumnType_JSON, Description: "When set, provides configuration for the amount to be adjusted by the customer during Checkout Sessions and Payment Links."},
+			{Name: "deleted", Type: proto.ColumnType_BOOL, Description: "True if the price is marked as deleted."},
+			{Name: "lookup_key", Type: proto.ColumnType_STRING, Description: "A lookup key used to retrieve prices dynamically from a static string."},
+			{Name: "metadata", Type: proto.ColumnType_JSON, Description: "Set of key-value pairs that you can attach to a price. This can be useful for storing additional information about the price in a structured format."},
+			{Name: "product_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("Product.ID"), Description: "ID of the product this price is associated with."},
+			{Name: "recurring", Type: proto.ColumnType_JSON, Description: "The recurring components of a price such as interval and usage_type."},
+			{Name: "recurring_interval", Type: proto.ColumnType_STRING, Transform: transform.FromField("Recurring.Interval"), Description: "The frequency at which a subscription is billed. One of day, week, month or year. Null for one-time prices."},
+			{Name: "recurring_interval_count", Type: proto.ColumnType_INT, Transform: transform.FromField("Recurring.IntervalCount"), Description: "The number of intervals (specified in the recurring_interval attribute) between subscription billings."},
+			{Name: "recurring_usage_type", Type: proto.ColumnType_STRING, Transform: transform.FromField("Recurring.UsageType"), Description: "Configures how the quantity per period should be determined. Can be either metered or licensed."},
+			{Name: "tax_behavior", Type: proto.ColumnType_STRING, Description: "Specifies whether the price is considered inclusive of taxes or exclusive of taxes. One of inclusive, exclusive, or unspecified."},
+			{Name: "tiers", Type: proto.ColumnType_JSON, Description: "Each element represents a pricing tier. This parameter requires billing_scheme to be set to tiered."},
+			{Name: "tiers_mode", Type: proto.ColumnType_STRING, Description: "Defines if the tiering price should be graduated or volume based. In volume-based tiering, the maximum quantity within a period determines the per unit price. In graduated tiering, pricing can change as the quantity grows."},
+			{Name: "transform_quantity", Type: proto.ColumnType_JSON, Description: "Apply a transformation to the reported usage or set quantity before computing the amount billed."},
+			{Name: "type", Type: proto.ColumnType_STRING, Description: "One of one_time or recurring depending on whether the price is for a one-time purchase or a recurring (subscription) purchase."},
+			{Name: "unit_amount", Type: proto.ColumnType_INT, Transform: transform.FromField("UnitAmount"), Description: "The unit amount in cents to be charged, represented as a whole integer if possible. Only set if billing_scheme=per_unit."},
+			{Name: "unit_amount_decimal", Type: proto.ColumnType_DOUBLE, Transform: transform.FromField("UnitAmountDecimal"), Description: "The unit amount in cents to be charged, represented as a decimal string with at most 12 decimal places. Only set if billing_scheme=per_unit."},
+		}),
+	}
+}
+
+func listPrice(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_price.listPrice", "connection_error", err)
+		return nil, err
+	}
+	params := &stripe.PriceListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	equalQuals := d.EqualsQuals
+	if equalQuals["currency"] != nil {
+		params.Currency = stripe.String(equalQuals["currency"].GetStringValue())
+	}
+	if equalQuals["product_id"] != nil {
+		params.Product = stripe.String(equalQuals["product_id"].GetStringValue())
+	}
+	if equalQuals["type"] != nil {
+		params.Type = stripe.String(equalQuals["type"].GetStringValue())
+	}
+	if equalQuals["recurring_interval"] != nil || equalQuals["recurring_usage_type"] != nil {
+		params.Recurring = &stripe.PriceListRecurringParams{}
+		if equalQuals["recurring_interval"] != nil {
+			params.Recurring.Interval = stripe.String(equalQuals["recurring_interval"].GetStringValue())
+		}
+		if equalQuals["recurring_usage_type"] != nil {
+			params.Recurring.UsageType = stripe.String(equalQuals["recurring_usage_type"].GetStringValue())
+		}
+	}
+
+	if lookupKey := equalQuals["lookup_key"].GetStringValue(); lookupKey != "" {
+		params.LookupKeys = stripe.StringSlice([]string{lookupKey})
+	}
+
+	// Comparison values
+	quals := d.Quals
+
+	if quals["active"] != nil {
+		for _, q := range quals["active"].Quals {
+			switch q.Operator {
+			case "=":
+				params.Active = stripe.Bool(q.Value.GetBoolValue())
+			case "<>":
+				params.Active = stripe.Bool(!q.Value.GetBoolValue())
+			}
+		}
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	err = listQueryRanges(ctx, d, []string{"created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.Created, params.CreatedRange = r["created"].listParams()
+		i := conn.Prices.List(&params)
+		for i.Next() {
+			if !stream(i.Price()) {
+				break
+			}
+		}
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_price.listPrice", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getPrice(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_price.getPrice", "connection_error", err)
+		return nil, err
+	}
+	quals := d.EqualsQuals
+	id := quals["id"].GetStringValue()
+	params := &stripe.PriceParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.Prices.Get(id, params)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_price.getPrice", "query_error", err, "id", id)
+		return nil, err
+	}
+	return item, nil
+}
@@ -26,6 +26,7 @@ func tableStripeProduct(ctx context.Context) *plugin.Table {
 			Hydrate:    getProduct,
 			KeyColumns: plugin.SingleColumn("id"),
 		},
+		GetMatrixItemFunc: connectedAccountMatrix,
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
 			{Name: "id", Type: proto.ColumnType_STRING, Description: "Unique identifier for the product."},
@@ -37,7 +38,6 @@ func tableStripeProduct(ctx context.Context) *plugin.Table {
 			{Name: "created", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("Created").Transform(transform.UnixToTimestamp), Description: "Time at which the product was created."},
 			{Name: "description", Type: proto.ColumnType_STRING, Description: "An arbitrary string attached to the product. Often useful for displaying to users."},
 			{Name: "images", Type: proto.ColumnType_JSON, Description: "A list of up to 8 URLs of images for this product, meant to be displayable to the customer."},
-			{Name: "livemode", Type: proto.ColumnType_BOOL, Description: "Has the value true if the product exists in live mode or the value false if the product exists in test mode."},
 			{Name: "metadata", Type: proto.ColumnType_JSON, Description: "Set of key-value pairs that you can attach to an product. This can be useful for storing additional information about the product in a structured format."},
 			{Name: "package_dimensions", Type: proto.ColumnType_JSON, Description: "The dimensions of this product for shipping purposes."},
 			{Name: "shippable", Type: proto.ColumnType_BOOL, Description: "Whether this product is shipped (i.e., physical goods)."},
@@ -57,8 +57,9 @@ func listProduct(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 
 	params := &stripe.ProductListParams{
 		ListParams: stripe.ListParams{
-			Context: ctx,
-			Limit:   stripe.Int64(100),
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
 		},
 	}
 
@@ -92,36 +93,6 @@ func listProduct(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 		}
 	}
 
-	if quals["created"] != nil {
-		for _, q := range quals["created"].Quals {
-			tsSecs := q.Value.GetTimestampValue().GetSeconds()
-			switch q.Operator {
-			case ">":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.GreaterThan = tsSecs
-			case ">=":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.GreaterThanOrEqual = tsSecs
-			case "=":
-				params.Created = stripe.Int64(tsSecs)
-			case "<=":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.LesserThanOrEqual = tsSecs
-			case "<":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.LesserThan = tsSecs
-			}
-		}
-	}
-
 	limit := d.QueryContext.Limit
 	if d.QueryContext.Limit != nil {
 		if *limit < *params.ListParams.Limit {
@@ -129,19 +100,23 @@ func listProduct(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 		}
 	}
 
-	var count int64
-	i := conn.Products.List(params)
-	for i.Next() {
-		d.StreamListItem(ctx, i.Product())
-		count++
-		if limit != nil {
-			if count >= *limit {
+	err = listQueryRanges(ctx, d, []string{"created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.Created, params.CreatedRange = r["created"].listParams()
+		i := conn.Products.List(&params)
+		for i.Next() {
+			if !stream(i.Product()) {
 				break
 			}
 		}
-	}
-	if err := i.Err(); err != nil {
-		plugin.Logger(ctx).Error("stripe_product.listProduct", "query_error", err, "params", params, "i", i)
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_product.listProduct", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -156,7 +131,13 @@ func getProduct(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 	}
 	quals := d.EqualsQuals
 	id := quals["id"].GetStringValue()
-	item, err := conn.Products.Get(id, &stripe.ProductParams{})
+	params := &stripe.ProductParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.Products.Get(id, params)
 	if err != nil {
 		plugin.Logger(ctx).Error("stripe_product.getProduct", "query_error", err, "id", id)
 		return nil, err
@@ -0,0 +1,235 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripePromotionCode(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_promotion_code",
+		Description: "Promotion codes are customer-redeemable codes for a coupon.",
+		List: &plugin.ListConfig{
+			Hydrate: listPromotionCodes,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "active", Operators: []string{"=", "<>"}, Require: plugin.Optional},
+				{Name: "code", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "coupon_id", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "created", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
+				{Name: "customer", Require: plugin.Optional, Operators: []string{"="}},
+			},
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getPromotionCode,
+			KeyColumns: plugin.SingleColumn("id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			// Basic fields
+			{
+				Name:        "id",
+				Description: "Unique identifier for the promotion code.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PromotionCode.ID"),
+			},
+			{
+				Name:        "code",
+				Description: "The customer-facing code, which is unique across active promotion codes of the account.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PromotionCode.Code"),
+			},
+			{
+				Name:        "active",
+				Description: "Whether the promotion code is currently active. Inactive codes cannot be redeemed.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("PromotionCode.Active"),
+			},
+			{
+				Name:        "coupon_id",
+				Description: "ID of the coupon the promotion code applies.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("CouponID"),
+			},
+			{
+				Name:        "created",
+				Description: "Time at which the promotion code was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("PromotionCode.Created").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "customer",
+				Description: "ID of the customer the promotion code is restricted to. If null, any customer can redeem it.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PromotionCode.Customer.ID"),
+			},
+			{
+				Name:        "expires_at",
+				Description: "Time at which the promotion code expires. If null, it does not expire.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("PromotionCode.ExpiresAt").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "max_redemptions",
+				Description: "Maximum number of times the promotion code can be redeemed. If null, there is no limit.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("PromotionCode.MaxRedemptions"),
+			},
+			{
+				Name:        "times_redeemed",
+				Description: "Number of times the promotion code has been redeemed.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("PromotionCode.TimesRedeemed"),
+			},
+
+			// JSON columns for complex data
+			{
+				Name:        "coupon",
+				Description: "The coupon the promotion code applies, for API versions that return it on the promotion code.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("PromotionCode.Coupon"),
+			},
+			{
+				Name:        "metadata",
+				Description: "Set of key-value pairs attached to the promotion code.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("PromotionCode.Metadata"),
+			},
+			{
+				Name:        "restrictions",
+				Description: "The restrictions on redeeming the promotion code, such as first time transactions only or a minimum amount.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("PromotionCode.Restrictions"),
+			},
+		}),
+	}
+}
+
+// promotionCode is a promotion code along with the ID of its coupon, which
+// depends on the API version.
+type promotionCode struct {
+	PromotionCode *stripe.PromotionCode
+	CouponID      string
+}
+
+// promotionCodeFields are the fields of a promotion code that depend on the
+// API version.
+type promotionCodeFields struct {
+	Promotion *struct {
+		Coupon json.RawMessage `json:"coupon"`
+	} `json:"promotion"`
+}
+
+func newPromotionCode(item *stripe.PromotionCode, f promotionCodeFields) *promotionCode {
+	row := &promotionCode{PromotionCode: item}
+	if item.Coupon != nil {
+		row.CouponID = item.Coupon.ID
+	}
+	setPromotionCodeVersionedFields(row, f)
+	return row
+}
+
+func listPromotionCodes(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_promotion_code.listPromotionCodes", "connection_error", err)
+		return nil, err
+	}
+	params := &stripe.PromotionCodeListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	q := d.EqualsQuals
+	if q["code"] != nil {
+		params.Code = stripe.String(q["code"].GetStringValue())
+	}
+	if q["coupon_id"] != nil {
+		params.Coupon = stripe.String(q["coupon_id"].GetStringValue())
+	}
+	if q["customer"] != nil {
+		params.Customer = stripe.String(q["customer"].GetStringValue())
+	}
+
+	// Comparison values
+	quals := d.Quals
+
+	if quals["active"] != nil {
+		for _, q := range quals["active"].Quals {
+			switch q.Operator {
+			case "=":
+				params.Active = stripe.Bool(q.Value.GetBoolValue())
+			case "<>":
+				params.Active = stripe.Bool(!q.Value.GetBoolValue())
+			}
+		}
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	err = listQueryRanges(ctx, d, []string{"created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.Created, params.CreatedRange = r["created"].listParams()
+		var page rawPage[promotionCodeFields]
+		i := conn.PromotionCodes.List(&params)
+		for i.Next() {
+			item := i.PromotionCode()
+			if !stream(newPromotionCode(item, page.get(i.PromotionCodeList().LastResponse, item.ID))) {
+				break
+			}
+		}
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_promotion_code.listPromotionCodes", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getPromotionCode(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_promotion_code.getPromotionCode", "connection_error", err)
+		return nil, err
+	}
+	quals := d.EqualsQuals
+	id := quals["id"].GetStringValue()
+	params := &stripe.PromotionCodeParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.PromotionCodes.Get(id, params)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_promotion_code.getPromotionCode", "query_error", err, "id", id)
+		return nil, err
+	}
+	return newPromotionCode(item, decodeRawFields[promotionCodeFields](item.LastResponse)), nil
+}
+
+// setPromotionCodeVersionedFields sets the coupon of a promotion code from its
+// promotion, for API versions that no longer return it on the promotion code.
+func setPromotionCodeVersionedFields(p *promotionCode, f promotionCodeFields) {
+	if p.CouponID == "" && f.Promotion != nil {
+		p.CouponID = expandableId(f.Promotion.Coupon)
+	}
+}
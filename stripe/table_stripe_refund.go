@@ -0,0 +1,204 @@
+package stripe
+
+import (
+	"context"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripeRefund(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_refund",
+		Description: "Refunds of charges and payment intents that were previously created.",
+		List: &plugin.ListConfig{
+			Hydrate: listRefunds,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "created", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
+				{Name: "charge", Require: plugin.Optional, Operators: []string{"="}},
+				{Name: "payment_intent", Require: plugin.Optional, Operators: []string{"="}},
+			},
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getRefund,
+			KeyColumns: plugin.SingleColumn("id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			// Basic fields
+			{
+				Name:        "id",
+				Description: "Unique identifier for the refund.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "amount",
+				Description: "Amount refunded, in the smallest currency unit.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "balance_transaction",
+				Description: "ID of the balance transaction that describes the impact on your account balance.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("BalanceTransaction.ID"),
+			},
+			{
+				Name:        "charge",
+				Description: "ID of the charge that was refunded.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Charge.ID"),
+			},
+			{
+				Name:        "created",
+				Description: "Timestamp when the refund was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Created").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "currency",
+				Description: "Currency of the refund.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "description",
+				Description: "Description of the refund.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "failure_balance_transaction",
+				Description: "ID of the balance transaction that describes the reversal of the balance on your account due to the refund failure.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("FailureBalanceTransaction.ID"),
+			},
+			{
+				Name:        "failure_reason",
+				Description: "Reason the refund failed, if it failed (e.g., lost_or_stolen_card, expired_or_canceled_card, unknown).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "instructions_email",
+				Description: "Email to which refund instructions were sent, if the refund required customer action.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "payment_intent",
+				Description: "ID of the payment intent that was refunded.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PaymentIntent.ID"),
+			},
+			{
+				Name:        "reason",
+				Description: "Reason for the refund (e.g., duplicate, fraudulent, requested_by_customer, expired_uncaptured_charge).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "receipt_number",
+				Description: "Transaction number that appears on email receipts sent for this refund.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "status",
+				Description: "Status of the refund (e.g., pending, requires_action, succeeded, failed, canceled).",
+				Type:        proto.ColumnType_STRING,
+			},
+
+			// JSON columns for complex data
+			{
+				Name:        "metadata",
+				Description: "Metadata associated with the refund.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "next_action",
+				Description: "If the refund requires customer action, the details of that action.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "source_transfer_reversal",
+				Description: "The transfer reversal that is associated with the refund, if the charge was created on behalf of a connected account.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "transfer_reversal",
+				Description: "The transfer reversal that is associated with the refund, if the charge was a destination charge.",
+				Type:        proto.ColumnType_JSON,
+			},
+		}),
+	}
+}
+
+func listRefunds(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_refund.listRefunds", "connection_error", err)
+		return nil, err
+	}
+	params := &stripe.RefundListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	q := d.EqualsQuals
+	if q["charge"] != nil {
+		params.Charge = stripe.String(q["charge"].GetStringValue())
+	}
+	if q["payment_intent"] != nil {
+		params.PaymentIntent = stripe.String(q["payment_intent"].GetStringValue())
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	err = listQueryRanges(ctx, d, []string{"created"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.Created, params.CreatedRange = r["created"].listParams()
+		i := conn.Refunds.List(&params)
+		for i.Next() {
+			if !stream(i.Refund()) {
+				break
+			}
+		}
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_refund.listRefunds", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getRefund(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_refund.getRefund", "connection_error", err)
+		return nil, err
+	}
+	quals := d.EqualsQuals
+	id := quals["id"].GetStringValue()
+	params := &stripe.RefundParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.Refunds.Get(id, params)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_refund.getRefund", "query_error", err, "id", id)
+		return nil, err
+	}
+	return item, nil
+}
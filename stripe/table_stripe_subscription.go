@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/client"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
@@ -21,13 +22,15 @@ func tableStripeSubscription(ctx context.Context) *plugin.Table {
 				{Name: "created", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
 				{Name: "current_period_end", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
 				{Name: "current_period_start", Operators: []string{">", ">=", "=", "<", "<="}, Require: plugin.Optional},
-				{Name: "status", Require: plugin.Optional},
+				{Name: "metadata", Operators: []string{"@>"}, Require: plugin.Optional},
+				{Name: "status", Operators: []string{"=", "<>"}, Require: plugin.Optional},
 			},
 		},
 		Get: &plugin.GetConfig{
 			Hydrate:    getSubscription,
 			KeyColumns: plugin.SingleColumn("id"),
 		},
+		GetMatrixItemFunc: connectedAccountMatrix,
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
 			{Name: "id", Type: proto.ColumnType_STRING, Description: "Unique identifier for the subscription."},
@@ -54,7 +57,6 @@ func tableStripeSubscription(ctx context.Context) *plugin.Table {
 			{Name: "items", Type: proto.ColumnType_JSON, Transform: transform.FromField("Items.Data"), Description: "List of subscription items, each with an attached price."},
 			//{Name: "latest_invoice", Type: proto.ColumnType_JSON, Description: "The most recent invoice this subscription has generated."},
 			{Name: "latest_invoice_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("LatestInvoice.ID"), Description: "ID of the most recent invoice this subscription has generated."},
-			{Name: "livemode", Type: proto.ColumnType_BOOL, Description: "Has the value true if the subscription exists in live mode or the value false if the subscription exists in test mode."},
 			{Name: "metadata", Type: proto.ColumnType_JSON, Description: "Set of key-value pairs that you can attach to an subscription. This can be useful for storing additional information about the subscription in a structured format."},
 			{Name: "next_pending_invoice_item_invoice", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("EndedAt").Transform(transform.UnixToTimestamp), Description: "Specifies the approximate timestamp on which any pending invoice items will be billed according to the schedule provided at pending_invoice_item_interval."},
 			{Name: "pause_collection", Type: proto.ColumnType_JSON, Description: "If specified, payment collection for this subscription will be paused."},
@@ -72,6 +74,14 @@ func tableStripeSubscription(ctx context.Context) *plugin.Table {
 	}
 }
 
+// subscriptionSearchFields are the subscription columns that can be filtered
+// using the Search API
+var subscriptionSearchFields = []searchField{
+	{Column: "created", Field: "created", Type: searchFieldTimestamp, ListFilter: true},
+	{Column: "metadata", Field: "metadata", Type: searchFieldMetadata},
+	{Column: "status", Field: "status", Type: searchFieldString, ListFilter: true},
+}
+
 func listSubscription(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
 	conn, err := connect(ctx, d)
 	if err != nil {
@@ -79,10 +89,22 @@ func listSubscription(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrat
 		return nil, err
 	}
 
+	// Filters the List endpoint does not support are pushed down using the
+	// Search API instead
+	if query := buildSearchQuery(d, subscriptionSearchFields, "collection_method", "customer_id", "current_period_end", "current_period_start"); query != "" {
+		// The List endpoint only returns canceled subscriptions when a status
+		// is given, so the search is restricted in the same way
+		if d.Quals["status"] == nil {
+			query += " AND -status:'canceled'"
+		}
+		return searchSubscriptions(ctx, d, conn, query)
+	}
+
 	params := &stripe.SubscriptionListParams{
 		ListParams: stripe.ListParams{
-			Context: ctx,
-			Limit:   stripe.Int64(100),
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
 		},
 	}
 
@@ -103,110 +125,88 @@ func listSubscription(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrat
 		params.Status = &status
 	}
 
-	// Comparison values
-	quals := d.Quals
-
-	if quals["created"] != nil {
-		for _, q := range quals["created"].Quals {
-			tsSecs := q.Value.GetTimestampValue().GetSeconds()
-			switch q.Operator {
-			case ">":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.GreaterThan = tsSecs
-			case ">=":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.GreaterThanOrEqual = tsSecs
-			case "=":
-				params.Created = &tsSecs
-			case "<=":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.LesserThanOrEqual = tsSecs
-			case "<":
-				if params.CreatedRange == nil {
-					params.CreatedRange = &stripe.RangeQueryParams{}
-				}
-				params.CreatedRange.LesserThan = tsSecs
-			}
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
 		}
 	}
 
-	if quals["current_period_start"] != nil {
-		for _, q := range quals["current_period_start"].Quals {
-			tsSecs := q.Value.GetTimestampValue().GetSeconds()
-			switch q.Operator {
-			case ">":
-				if params.CurrentPeriodStartRange == nil {
-					params.CurrentPeriodStartRange = &stripe.RangeQueryParams{}
-				}
-				params.CurrentPeriodStartRange.GreaterThan = tsSecs
-			case ">=":
-				if params.CurrentPeriodStartRange == nil {
-					params.CurrentPeriodStartRange = &stripe.RangeQueryParams{}
-				}
-				params.CurrentPeriodStartRange.GreaterThanOrEqual = tsSecs
-			case "=":
-				params.CurrentPeriodStart = stripe.Int64(tsSecs)
-			case "<=":
-				if params.CurrentPeriodStartRange == nil {
-					params.CurrentPeriodStartRange = &stripe.RangeQueryParams{}
-				}
-				params.CurrentPeriodStartRange.LesserThanOrEqual = tsSecs
-			case "<":
-				if params.CurrentPeriodStartRange == nil {
-					params.CurrentPeriodStartRange = &stripe.RangeQueryParams{}
-				}
-				params.CurrentPeriodStartRange.LesserThan = tsSecs
+	err = listQueryRanges(ctx, d, []string{"created", "current_period_start", "current_period_end"}, func(ctx context.Context, r queryRanges, stream func(item interface{}) bool) error {
+		params := *params
+		params.Context = ctx
+		params.Created, params.CreatedRange = r["created"].listParams()
+		params.CurrentPeriodStart, params.CurrentPeriodStartRange = r["current_period_start"].listParams()
+		params.CurrentPeriodEnd, params.CurrentPeriodEndRange = r["current_period_end"].listParams()
+		var page rawPage[subscriptionFields]
+		i := conn.Subscriptions.List(&params)
+		for i.Next() {
+			item := i.Subscription()
+			setSubscriptionVersionedFields(item, page.get(i.SubscriptionList().LastResponse, item.ID))
+			if !stream(item) {
+				break
 			}
 		}
+		if err := i.Err(); err != nil {
+			plugin.Logger(ctx).Error("stripe_subscription.listSubscription", "query_error", err, "params", params, "i", i)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if quals["current_period_end"] != nil {
-		for _, q := range quals["current_period_end"].Quals {
-			tsSecs := q.Value.GetTimestampValue().GetSeconds()
-			switch q.Operator {
-			case ">":
-				if params.CurrentPeriodEndRange == nil {
-					params.CurrentPeriodEndRange = &stripe.RangeQueryParams{}
-				}
-				params.CurrentPeriodEndRange.GreaterThan = tsSecs
-			case ">=":
-				if params.CurrentPeriodEndRange == nil {
-					params.CurrentPeriodEndRange = &stripe.RangeQueryParams{}
-				}
-				params.CurrentPeriodEndRange.GreaterThanOrEqual = tsSecs
-			case "=":
-				params.CurrentPeriodEnd = stripe.Int64(tsSecs)
-			case "<=":
-				if params.CurrentPeriodEndRange == nil {
-					params.CurrentPeriodEndRange = &stripe.RangeQueryParams{}
-				}
-				params.CurrentPeriodEndRange.LesserThanOrEqual = tsSecs
-			case "<":
-				if params.CurrentPeriodEndRange == nil {
-					params.CurrentPeriodEndRange = &stripe.RangeQueryParams{}
-				}
-				params.CurrentPeriodEndRange.LesserThan = tsSecs
-			}
-		}
+	return nil, nil
+}
+
+func getSubscription(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_subscription.getSubscription", "connection_error", err)
+		return nil, err
+	}
+	quals := d.EqualsQuals
+	id := quals["id"].GetStringValue()
+	params := &stripe.SubscriptionParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.Subscriptions.Get(id, params)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_subscription.getSubscription", "query_error", err, "id", id)
+		return nil, err
+	}
+	setSubscriptionVersionedFields(item, decodeRawFields[subscriptionFields](item.LastResponse))
+	return item, nil
+}
+
+func searchSubscriptions(ctx context.Context, d *plugin.QueryData, conn *client.API, query string) (interface{}, error) {
+	params := &stripe.SubscriptionSearchParams{
+		SearchParams: stripe.SearchParams{
+			Context:       ctx,
+			Query:         query,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
 	}
 
 	limit := d.QueryContext.Limit
 	if d.QueryContext.Limit != nil {
-		if *limit < *params.ListParams.Limit {
-			params.ListParams.Limit = limit
+		if *limit < *params.SearchParams.Limit {
+			params.SearchParams.Limit = limit
 		}
 	}
 
+	var page rawPage[subscriptionFields]
 	var count int64
-	i := conn.Subscriptions.List(params)
+	i := conn.Subscriptions.Search(params)
 	for i.Next() {
-		d.StreamListItem(ctx, i.Subscription())
+		item := i.Subscription()
+		setSubscriptionVersionedFields(item, page.get(i.SubscriptionSearchResult().LastResponse, item.ID))
+		d.StreamListItem(ctx, item)
 		count++
 		if limit != nil {
 			if count >= *limit {
@@ -215,25 +215,34 @@ func listSubscription(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrat
 		}
 	}
 	if err := i.Err(); err != nil {
-		plugin.Logger(ctx).Error("stripe_subscription.listSubscription", "query_error", err, "params", params, "i", i)
+		plugin.Logger(ctx).Error("stripe_subscription.searchSubscriptions", "query_error", err, "query", query)
 		return nil, err
 	}
 
 	return nil, nil
 }
 
-func getSubscription(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
-	conn, err := connect(ctx, d)
-	if err != nil {
-		plugin.Logger(ctx).Error("stripe_subscription.getSubscription", "connection_error", err)
-		return nil, err
+// subscriptionFields are the fields of a subscription that depend on the API
+// version.
+type subscriptionFields struct {
+	Items *struct {
+		Data []subscriptionItemFields `json:"data"`
+	} `json:"items"`
+}
+
+// setSubscriptionVersionedFields sets the current period of a subscription
+// from its items, for API versions that no longer return it on the
+// subscription. The period spans the periods of all the items.
+func setSubscriptionVersionedFields(s *stripe.Subscription, f subscriptionFields) {
+	if s == nil || s.CurrentPeriodStart != 0 || f.Items == nil {
+		return
 	}
-	quals := d.EqualsQuals
-	id := quals["id"].GetStringValue()
-	item, err := conn.Subscriptions.Get(id, &stripe.SubscriptionParams{})
-	if err != nil {
-		plugin.Logger(ctx).Error("stripe_subscription.getSubscription", "query_error", err, "id", id)
-		return nil, err
+	for _, item := range f.Items.Data {
+		if item.CurrentPeriodStart != 0 && (s.CurrentPeriodStart == 0 || item.CurrentPeriodStart < s.CurrentPeriodStart) {
+			s.CurrentPeriodStart = item.CurrentPeriodStart
+		}
+		if item.CurrentPeriodEnd > s.CurrentPeriodEnd {
+			s.CurrentPeriodEnd = item.CurrentPeriodEnd
+		}
 	}
-	return item, nil
 }
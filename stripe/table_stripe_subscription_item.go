@@ -2,6 +2,7 @@ package stripe
 
 import (
 	"context"
+	"slices"
 	//"time"
 
 	"github.com/stripe/stripe-go/v76"
@@ -18,17 +19,37 @@ func tableStripeSubscriptionItem(ctx context.Context) *plugin.Table {
 			KeyColumns: plugin.SingleColumn("subscription_id"),
 			Hydrate:    listSubscriptionItem,
 		},
+		GetMatrixItemFunc: connectedAccountMatrix,
 		Columns: commonColumns([]*plugin.Column{
 			// Add columns relevant to SubscriptionItems here
-			{Name: "id", Type: proto.ColumnType_STRING, Description: "Unique identifier for the subscription."},
-			{Name: "plan", Type: proto.ColumnType_JSON, Transform: transform.FromField("Plan"), Description: "A plan represents a billing configuration. (Deprecated)"},
-			{Name: "price", Type: proto.ColumnType_JSON, Transform: transform.FromField("Price"), Description: "A price represents a unit cost for a product, specifying the amount, currency, and billing frequency."},
-			{Name: "subscription_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("Subscription"), Description: "The ID of the subscription this item belongs to."},
+			{Name: "id", Type: proto.ColumnType_STRING, Transform: transform.FromField("SubscriptionItem.ID"), Description: "Unique identifier for the subscription."},
+			{Name: "current_period_end", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("CurrentPeriodEnd").Transform(transform.UnixToTimestamp), Description: "End of the current period that the subscription item has been invoiced for."},
+			{Name: "current_period_start", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("CurrentPeriodStart").Transform(transform.UnixToTimestamp), Description: "Start of the current period that the subscription item has been invoiced for."},
+			{Name: "plan", Type: proto.ColumnType_JSON, Transform: transform.FromField("SubscriptionItem.Plan"), Description: "A plan represents a billing configuration. (Deprecated)"},
+			{Name: "price", Type: proto.ColumnType_JSON, Transform: transform.FromField("SubscriptionItem.Price"), Description: "A price represents a unit cost for a product, specifying the amount, currency, and billing frequency."},
+			{Name: "subscription_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("SubscriptionItem.Subscription"), Description: "The ID of the subscription this item belongs to."},
 			{Name: "usage_record_summaries", Type: proto.ColumnType_JSON, Hydrate: listUsageRecordSummaries, Transform: transform.FromValue()},
 		}),
 	}
 }
 
+// subscriptionItem is a subscription item along with its current period.
+// From the basil API version the period is returned on subscription items,
+// and before it only on their subscription.
+type subscriptionItem struct {
+	SubscriptionItem   *stripe.SubscriptionItem
+	CurrentPeriodStart int64
+	CurrentPeriodEnd   int64
+}
+
+// subscriptionItemFields are the fields of a subscription item that depend on
+// the API version. From the basil API version, the current period is returned
+// on subscription items instead of subscriptions.
+type subscriptionItemFields struct {
+	CurrentPeriodStart int64 `json:"current_period_start"`
+	CurrentPeriodEnd   int64 `json:"current_period_end"`
+}
+
 // listSubscriptionItem lists all subscription items
 func listSubscriptionItem(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
 	conn, err := connect(ctx, d)
@@ -41,20 +62,74 @@ func listSubscriptionItem(ctx context.Context, d *plugin.QueryData, _ *plugin.Hy
 	plugin.Logger(ctx).Debug("stripe_subscription.listSubscriptionItem", "subscription_id", subscription_id)
 
 	params := &stripe.SubscriptionItemListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
 		Subscription: stripe.String(subscription_id),
 	}
 
-	i := conn.SubscriptionItems.List(params)
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
 
+	// Before the basil API version the current period is only returned on
+	// the subscription, so it is retrieved when the period is requested
+	var subscription *stripe.Subscription
+	if !apiVersionAtLeast(d, apiVersionBasil) && (slices.Contains(d.QueryContext.Columns, "current_period_start") || slices.Contains(d.QueryContext.Columns, "current_period_end")) {
+		subscription, err = conn.Subscriptions.Get(subscription_id, &stripe.SubscriptionParams{
+			Params: stripe.Params{
+				Context:       ctx,
+				StripeAccount: connectedAccount(ctx),
+			},
+		})
+		if err != nil {
+			if isNotFoundError(err) {
+				return nil, nil
+			}
+			plugin.Logger(ctx).Error("stripe_subscription.listSubscriptionItem", "query_error", err, "subscription_id", subscription_id)
+			return nil, err
+		}
+	}
+
+	var page rawPage[subscriptionItemFields]
+	var count int64
+	i := conn.SubscriptionItems.List(params)
 	for i.Next() {
-		d.StreamListItem(ctx, i.SubscriptionItem())
+		item := &subscriptionItem{SubscriptionItem: i.SubscriptionItem()}
+		if subscription != nil {
+			item.CurrentPeriodStart = subscription.CurrentPeriodStart
+			item.CurrentPeriodEnd = subscription.CurrentPeriodEnd
+		} else {
+			f := page.get(i.SubscriptionItemList().LastResponse, item.SubscriptionItem.ID)
+			item.CurrentPeriodStart = f.CurrentPeriodStart
+			item.CurrentPeriodEnd = f.CurrentPeriodEnd
+		}
+		d.StreamListItem(ctx, item)
+		count++
+		if limit != nil {
+			if count >= *limit {
+				break
+			}
+		}
+	}
+	if err := i.Err(); err != nil {
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		plugin.Logger(ctx).Error("stripe_subscription.listSubscriptionItem", "query_error", err, "params", params, "i", i)
+		return nil, err
 	}
 
 	return nil, nil
 }
 
 func listUsageRecordSummaries(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
-	item := h.Item.(*stripe.SubscriptionItem)
+	item := h.Item.(*subscriptionItem).SubscriptionItem
 
 	plugin.Logger(ctx).Debug("stripe_subscription.listUsageRecordSummaries", "item", item)
 
@@ -65,6 +140,10 @@ func listUsageRecordSummaries(ctx context.Context, d *plugin.QueryData, h *plugi
 	}
 
 	params := &stripe.SubscriptionItemUsageRecordSummariesParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
 		SubscriptionItem: stripe.String(item.ID),
 	}
 
@@ -74,6 +153,10 @@ func listUsageRecordSummaries(ctx context.Context, d *plugin.QueryData, h *plugi
 		summary := u.UsageRecordSummary()
 		summaries = append(summaries, summary)
 	}
+	if err := u.Err(); err != nil {
+		plugin.Logger(ctx).Error("stripe_subscription.listUsageRecordSummaries", "query_error", err, "params", params, "u", u)
+		return nil, err
+	}
 
 	return summaries, nil
 }
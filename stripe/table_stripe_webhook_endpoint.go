@@ -0,0 +1,192 @@
+package stripe
+
+import (
+	"context"
+	"strings"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableStripeWebhookEndpoint(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "stripe_webhook_endpoint",
+		Description: "Webhook endpoints are the URLs Stripe sends events to.",
+		List: &plugin.ListConfig{
+			Hydrate: listWebhookEndpoints,
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getWebhookEndpoint,
+			KeyColumns: plugin.SingleColumn("id"),
+		},
+		GetMatrixItemFunc: connectedAccountMatrix,
+		Columns: commonColumns([]*plugin.Column{
+			// Basic fields
+			{
+				Name:        "id",
+				Description: "Unique identifier for the webhook endpoint.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("WebhookEndpoint.ID"),
+			},
+			{
+				Name:        "url",
+				Description: "The URL of the webhook endpoint.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("WebhookEndpoint.URL"),
+			},
+			{
+				Name:        "status",
+				Description: "The status of the webhook endpoint, either enabled or disabled.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("WebhookEndpoint.Status"),
+			},
+			{
+				Name:        "application",
+				Description: "The ID of the Connect application the webhook endpoint belongs to, if any.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("WebhookEndpoint.Application"),
+			},
+			{
+				Name:        "created",
+				Description: "Time at which the webhook endpoint was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("WebhookEndpoint.Created").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "description",
+				Description: "An optional description of what the webhook endpoint is used for.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("WebhookEndpoint.Description"),
+			},
+			{
+				Name:        "event_api_version",
+				Description: "The API version events are rendered as for this webhook endpoint, or null if it uses the default API version of the account.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("WebhookEndpoint.APIVersion"),
+			},
+
+			// Derived columns
+			{
+				Name:        "uses_account_default_api_version",
+				Description: "True if events are rendered as the default API version of the account, because the webhook endpoint does not set its own API version.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("UsesAccountDefaultAPIVersion"),
+			},
+			{
+				Name:        "listens_to_all_events",
+				Description: "True if the webhook endpoint is sent all events, i.e. its enabled events include *.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("ListensToAllEvents"),
+			},
+			{
+				Name:        "uses_https",
+				Description: "True if the URL of the webhook endpoint uses HTTPS.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("UsesHTTPS"),
+			},
+
+			// JSON columns for complex data
+			{
+				Name:        "enabled_events",
+				Description: "The list of events enabled for the webhook endpoint. [\"*\"] indicates that all events are enabled, except those that require explicit selection.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("WebhookEndpoint.EnabledEvents"),
+			},
+			{
+				Name:        "metadata",
+				Description: "Set of key-value pairs attached to the webhook endpoint.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("WebhookEndpoint.Metadata"),
+			},
+		}),
+	}
+}
+
+// webhookEndpoint is a webhook endpoint along with the columns derived from
+// its configuration.
+type webhookEndpoint struct {
+	WebhookEndpoint              *stripe.WebhookEndpoint
+	UsesAccountDefaultAPIVersion bool
+	ListensToAllEvents           bool
+	UsesHTTPS                    bool
+}
+
+func newWebhookEndpoint(item *stripe.WebhookEndpoint) *webhookEndpoint {
+	row := &webhookEndpoint{
+		WebhookEndpoint: item,
+		// Stripe does not return the default API version of an account, but
+		// endpoints without an API version of their own use it
+		UsesAccountDefaultAPIVersion: item.APIVersion == "",
+		UsesHTTPS:                    strings.HasPrefix(strings.ToLower(item.URL), "https://"),
+	}
+	for _, e := range item.EnabledEvents {
+		if e == "*" {
+			row.ListensToAllEvents = true
+		}
+	}
+	return row
+}
+
+func listWebhookEndpoints(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_webhook_endpoint.listWebhookEndpoints", "connection_error", err)
+		return nil, err
+	}
+	params := &stripe.WebhookEndpointListParams{
+		ListParams: stripe.ListParams{
+			Context:       ctx,
+			Limit:         stripe.Int64(100),
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < *params.ListParams.Limit {
+			params.ListParams.Limit = limit
+		}
+	}
+
+	var count int64
+	i := conn.WebhookEndpoints.List(params)
+	for i.Next() {
+		d.StreamListItem(ctx, newWebhookEndpoint(i.WebhookEndpoint()))
+		count++
+		if limit != nil {
+			if count >= *limit {
+				break
+			}
+		}
+	}
+	if err := i.Err(); err != nil {
+		plugin.Logger(ctx).Error("stripe_webhook_endpoint.listWebhookEndpoints", "query_error", err, "params", params, "i", i)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getWebhookEndpoint(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_webhook_endpoint.getWebhookEndpoint", "connection_error", err)
+		return nil, err
+	}
+	quals := d.EqualsQuals
+	id := quals["id"].GetStringValue()
+	params := &stripe.WebhookEndpointParams{
+		Params: stripe.Params{
+			Context:       ctx,
+			StripeAccount: connectedAccount(ctx),
+		},
+	}
+	item, err := conn.WebhookEndpoints.Get(id, params)
+	if err != nil {
+		plugin.Logger(ctx).Error("stripe_webhook_endpoint.getWebhookEndpoint", "query_error", err, "id", id)
+		return nil, err
+	}
+	return newWebhookEndpoint(item), nil
+}
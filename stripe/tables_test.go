@@ -0,0 +1,1525 @@
+package stripe
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/context_key"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+const (
+	testStart = 1700000000
+	testEnd   = 1700086400
+)
+
+func TestListParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		hydrate plugin.HydrateFunc
+		quals   []*quals.Qual
+		path    string
+		object  string
+		want    url.Values
+	}{
+		{
+			name:    "account",
+			hydrate: listAccount,
+			path:    "/v1/account",
+			want:    url.Values{},
+		},
+		{
+			name:    "balance transaction",
+			hydrate: listBalanceTransactions,
+			quals: []*quals.Qual{
+				timestampQual("available_on", ">", testStart),
+				timestampQual("created", "<=", testEnd),
+				stringQual("currency", "=", "usd"),
+				stringQual("payout", "=", "po_1"),
+				stringQual("source", "=", "ch_1"),
+				stringQual("type", "=", "charge"),
+			},
+			path:   "/v1/balance_transactions",
+			object: "balance_transaction",
+			want: url.Values{
+				"created[lte]": {"1700086400"},
+				"currency":     {"usd"},
+				"limit":        {"100"},
+				"payout":       {"po_1"},
+				"source":       {"ch_1"},
+				"type":         {"charge"},
+			},
+		},
+		{
+			name:    "charge",
+			hydrate: listCharges,
+			quals: []*quals.Qual{
+				timestampQual("created", ">", testStart),
+				timestampQual("created", "<=", testEnd),
+				stringQual("customer", "=", "cus_1"),
+				stringQual("payment_intent", "=", "pi_1"),
+				stringQual("transfer_group", "=", "group_1"),
+			},
+			path:   "/v1/charges",
+			object: "charge",
+			want: url.Values{
+				"created[gte]":   {"1700000001"},
+				"created[lte]":   {"1700086400"},
+				"customer":       {"cus_1"},
+				"limit":          {"100"},
+				"payment_intent": {"pi_1"},
+				"transfer_group": {"group_1"},
+			},
+		},
+		{
+			name:    "charge search",
+			hydrate: listCharges,
+			quals: []*quals.Qual{
+				int64Qual("amount", ">=", 1000),
+				timestampQual("created", ">", testStart),
+				boolQual("refunded", "=", false),
+				stringQual("status", "<>", "failed"),
+			},
+			path:   "/v1/charges/search",
+			object: "charge",
+			want: url.Values{
+				"limit": {"100"},
+				"query": {"amount>=1000 AND created>1700000000 AND refunded:'false' AND -status:'failed'"},
+			},
+		},
+		{
+			name:    "charge with list only column does not search",
+			hydrate: listCharges,
+			quals: []*quals.Qual{
+				stringQual("payment_intent", "=", "pi_1"),
+				stringQual("status", "<>", "failed"),
+			},
+			path:   "/v1/charges",
+			object: "charge",
+			want: url.Values{
+				"limit":          {"100"},
+				"payment_intent": {"pi_1"},
+			},
+		},
+		{
+			name:    "checkout session",
+			hydrate: listCheckoutSessions,
+			quals: []*quals.Qual{
+				timestampQual("created", ">=", testStart),
+				timestampQual("created", "<", testEnd),
+				stringQual("customer", "=", "cus_1"),
+				stringQual("payment_intent", "=", "pi_1"),
+				stringQual("status", "=", "expired"),
+				stringQual("subscription", "=", "sub_1"),
+			},
+			path:   "/v1/checkout/sessions",
+			object: "checkout.session",
+			want: url.Values{
+				"created[gte]":   {"1700000000"},
+				"created[lte]":   {"1700086399"},
+				"customer":       {"cus_1"},
+				"limit":          {"100"},
+				"payment_intent": {"pi_1"},
+				"status":         {"expired"},
+				"subscription":   {"sub_1"},
+			},
+		},
+		{
+			name:    "checkout session line item",
+			hydrate: listCheckoutSessionLineItems,
+			quals: []*quals.Qual{
+				stringQual("session_id", "=", "cs_1"),
+			},
+			path:   "/v1/checkout/sessions/cs_1/line_items",
+			object: "item",
+			want: url.Values{
+				"limit": {"100"},
+			},
+		},
+		{
+			name:    "coupon",
+			hydrate: listCoupon,
+			quals: []*quals.Qual{
+				timestampQual("created", "=", testStart),
+			},
+			path:   "/v1/coupons",
+			object: "coupon",
+			want: url.Values{
+				"created": {"1700000000"},
+				"limit":   {"100"},
+			},
+		},
+		{
+			name:    "customer",
+			hydrate: listCustomer,
+			quals: []*quals.Qual{
+				timestampQual("created", ">=", testStart),
+				timestampQual("created", "<", testEnd),
+				stringQual("email", "=", "jane@example.com"),
+			},
+			path:   "/v1/customers",
+			object: "customer",
+			want: url.Values{
+				"created[gte]": {"1700000000"},
+				"created[lte]": {"1700086399"},
+				"email":        {"jane@example.com"},
+				"limit":        {"100"},
+			},
+		},
+		{
+			name:    "customer search",
+			hydrate: listCustomer,
+			quals: []*quals.Qual{
+				jsonbQual("metadata", "@>", `{"plan":"pro"}`),
+				stringQual("name", "~~", "%Acme%"),
+			},
+			path:   "/v1/customers/search",
+			object: "customer",
+			want: url.Values{
+				"limit": {"100"},
+				"query": {"metadata['plan']:'pro' AND name~'Acme'"},
+			},
+		},
+		{
+			name:    "dispute",
+			hydrate: listDisputes,
+			quals: []*quals.Qual{
+				stringQual("charge", "=", "ch_1"),
+				timestampQual("created", "<", testEnd),
+				stringQual("payment_intent", "=", "pi_1"),
+			},
+			path:   "/v1/disputes",
+			object: "dispute",
+			want: url.Values{
+				"charge":         {"ch_1"},
+				"created[lte]":   {"1700086399"},
+				"limit":          {"100"},
+				"payment_intent": {"pi_1"},
+			},
+		},
+		{
+			name:    "invoice",
+			hydrate: listInvoice,
+			quals: []*quals.Qual{
+				stringQual("collection_method", "=", "send_invoice"),
+				timestampQual("created", ">=", testStart),
+				timestampQual("due_date", "<", testEnd),
+				stringQual("status", "=", "open"),
+				stringQual("subscription_id", "=", "sub_1"),
+			},
+			path:   "/v1/invoices",
+			object: "invoice",
+			want: url.Values{
+				"collection_method": {"send_invoice"},
+				"created[gte]":      {"1700000000"},
+				"due_date[lte]":     {"1700086399"},
+				"expand[0]":         {"data.default_payment_method"},
+				"expand[1]":         {"data.default_source"},
+				"expand[2]":         {"data.subscription"},
+				"limit":             {"100"},
+				"status":            {"open"},
+				"subscription":      {"sub_1"},
+			},
+		},
+		{
+			name:    "payment intent",
+			hydrate: listPaymentIntents,
+			quals: []*quals.Qual{
+				timestampQual("created", ">", testStart),
+				stringQual("customer", "=", "cus_1"),
+			},
+			path:   "/v1/payment_intents",
+			object: "payment_intent",
+			want: url.Values{
+				"created[gte]": {"1700000001"},
+				"customer":     {"cus_1"},
+				"limit":        {"100"},
+			},
+		},
+		{
+			name:    "payment link",
+			hydrate: listPaymentLinks,
+			quals: []*quals.Qual{
+				boolQual("active", "<>", true),
+			},
+			path:   "/v1/payment_links",
+			object: "payment_link",
+			want: url.Values{
+				"active": {"false"},
+				"limit":  {"100"},
+			},
+		},
+		{
+			name:    "payout",
+			hydrate: listPayouts,
+			quals: []*quals.Qual{
+				timestampQual("arrival_date", ">=", testStart),
+				timestampQual("arrival_date", "<=", testEnd),
+				timestampQual("created", "=", testStart),
+				stringQual("destination", "=", "ba_1"),
+				stringQual("status", "=", "paid"),
+			},
+			path:   "/v1/payouts",
+			object: "payout",
+			want: url.Values{
+				"arrival_date[gte]": {"1700000000"},
+				"arrival_date[lte]": {"1700086400"},
+				"created":           {"1700000000"},
+				"destination":       {"ba_1"},
+				"limit":             {"100"},
+				"status":            {"paid"},
+			},
+		},
+		{
+			name:    "plan",
+			hydrate: listPlan,
+			quals: []*quals.Qual{
+				boolQual("active", "<>", false),
+				timestampQual("created", ">", testStart),
+				stringQual("product_id", "=", "prod_1"),
+			},
+			path:   "/v1/plans",
+			object: "plan",
+			want: url.Values{
+				"active":       {"true"},
+				"created[gte]": {"1700000001"},
+				"limit":        {"100"},
+				"product":      {"prod_1"},
+			},
+		},
+		{
+			name:    "price",
+			hydrate: listPrice,
+			quals: []*quals.Qual{
+				boolQual("active", "=", true),
+				stringQual("currency", "=", "usd"),
+				stringQual("lookup_key", "=", "standard_monthly"),
+				stringQual("product_id", "=", "prod_1"),
+				stringQual("recurring_interval", "=", "month"),
+				stringQual("recurring_usage_type", "=", "metered"),
+				stringQual("type", "=", "recurring"),
+			},
+			path:   "/v1/prices",
+			object: "price",
+			want: url.Values{
+				"active":                {"true"},
+				"currency":              {"usd"},
+				"limit":                 {"100"},
+				"lookup_keys[0]":        {"standard_monthly"},
+				"product":               {"prod_1"},
+				"recurring[interval]":   {"month"},
+				"recurring[usage_type]": {"metered"},
+				"type":                  {"recurring"},
+			},
+		},
+		{
+			name:    "product",
+			hydrate: listProduct,
+			quals: []*quals.Qual{
+				boolQual("active", "=", true),
+				timestampQual("created", "<=", testEnd),
+				boolQual("shippable", "<>", true),
+				stringQual("url", "=", "https://example.com"),
+			},
+			path:   "/v1/products",
+			object: "product",
+			want: url.Values{
+				"active":       {"true"},
+				"created[lte]": {"1700086400"},
+				"limit":        {"100"},
+				"shippable":    {"false"},
+				"url":          {"https://example.com"},
+			},
+		},
+		{
+			name:    "promotion code",
+			hydrate: listPromotionCodes,
+			quals: []*quals.Qual{
+				boolQual("active", "=", true),
+				stringQual("code", "=", "SUMMER"),
+				stringQual("coupon_id", "=", "co_1"),
+				timestampQual("created", ">=", testStart),
+				stringQual("customer", "=", "cus_1"),
+			},
+			path:   "/v1/promotion_codes",
+			object: "promotion_code",
+			want: url.Values{
+				"active":       {"true"},
+				"code":         {"SUMMER"},
+				"coupon":       {"co_1"},
+				"created[gte]": {"1700000000"},
+				"customer":     {"cus_1"},
+				"limit":        {"100"},
+			},
+		},
+		{
+			name:    "refund",
+			hydrate: listRefunds,
+			quals: []*quals.Qual{
+				stringQual("charge", "=", "ch_1"),
+				timestampQual("created", ">=", testStart),
+				stringQual("payment_intent", "=", "pi_1"),
+			},
+			path:   "/v1/refunds",
+			object: "refund",
+			want: url.Values{
+				"charge":         {"ch_1"},
+				"created[gte]":   {"1700000000"},
+				"limit":          {"100"},
+				"payment_intent": {"pi_1"},
+			},
+		},
+		{
+			name:    "subscription",
+			hydrate: listSubscription,
+			quals: []*quals.Qual{
+				stringQual("collection_method", "=", "charge_automatically"),
+				timestampQual("current_period_end", "<", testEnd),
+				timestampQual("current_period_start", ">", testStart),
+				stringQual("customer_id", "=", "cus_1"),
+				stringQual("status", "=", "active"),
+			},
+			path:   "/v1/subscriptions",
+			object: "subscription",
+			want: url.Values{
+				"collection_method":         {"charge_automatically"},
+				"current_period_end[lte]":   {"1700086399"},
+				"current_period_start[gte]": {"1700000001"},
+				"customer":                  {"cus_1"},
+				"limit":                     {"100"},
+				"status":                    {"active"},
+			},
+		},
+		{
+			name:    "subscription search",
+			hydrate: listSubscription,
+			quals: []*quals.Qual{
+				jsonbQual("metadata", "@>", `{"team":"growth"}`),
+				stringQual("status", "=", "active"),
+			},
+			path:   "/v1/subscriptions/search",
+			object: "subscription",
+			want: url.Values{
+				"limit": {"100"},
+				"query": {"metadata['team']:'growth' AND status:'active'"},
+			},
+		},
+		{
+			name:    "subscription search without status",
+			hydrate: listSubscription,
+			quals: []*quals.Qual{
+				jsonbQual("metadata", "@>", `{"team":"growth"}`),
+			},
+			path:   "/v1/subscriptions/search",
+			object: "subscription",
+			want: url.Values{
+				"limit": {"100"},
+				"query": {"metadata['team']:'growth' AND -status:'canceled'"},
+			},
+		},
+		{
+			name:    "credit note",
+			hydrate: listCreditNotes,
+			quals: []*quals.Qual{
+				stringQual("customer", "=", "cus_1"),
+				stringQual("invoice", "=", "in_1"),
+			},
+			path:   "/v1/credit_notes",
+			object: "credit_note",
+			want: url.Values{
+				"customer": {"cus_1"},
+				"invoice":  {"in_1"},
+				"limit":    {"100"},
+			},
+		},
+		{
+			name:    "credit note line item",
+			hydrate: listCreditNoteLineItems,
+			quals: []*quals.Qual{
+				stringQual("credit_note_id", "=", "cn_1"),
+			},
+			path:   "/v1/credit_notes/cn_1/lines",
+			object: "credit_note_line_item",
+			want: url.Values{
+				"limit": {"100"},
+			},
+		},
+		{
+			name:    "event",
+			hydrate: listEvents,
+			quals: []*quals.Qual{
+				timestampQual("created", ">=", testStart),
+				boolQual("delivery_success", "=", false),
+				stringQual("type", "~~", "customer.subscription.%"),
+			},
+			path:   "/v1/events",
+			object: "event",
+			want: url.Values{
+				"created[gte]":     {"1700000000"},
+				"delivery_success": {"false"},
+				"limit":            {"100"},
+				"type":             {"customer.subscription.*"},
+			},
+		},
+		{
+			// Steampipe only passes IN lists through when several columns have
+			// them, and Postgres filters the rows
+			name:    "event type list",
+			hydrate: listEvents,
+			quals: []*quals.Qual{
+				stringListQual("type", "invoice.paid", "invoice.payment_failed"),
+			},
+			path:   "/v1/events",
+			object: "event",
+			want: url.Values{
+				"limit": {"100"},
+			},
+		},
+		{
+			name:    "invoice item",
+			hydrate: listInvoiceItems,
+			quals: []*quals.Qual{
+				timestampQual("created", ">=", testStart),
+				stringQual("customer", "=", "cus_1"),
+				stringQual("invoice", "=", "in_1"),
+				boolQual("pending", "<>", false),
+			},
+			path:   "/v1/invoiceitems",
+			object: "invoiceitem",
+			want: url.Values{
+				"created[gte]": {"1700000000"},
+				"customer":     {"cus_1"},
+				"invoice":      {"in_1"},
+				"limit":        {"100"},
+				"pending":      {"true"},
+			},
+		},
+		{
+			name:    "invoice line item",
+			hydrate: listInvoiceLineItems,
+			quals: []*quals.Qual{
+				stringQual("invoice_id", "=", "in_1"),
+			},
+			path:   "/v1/invoices/in_1/lines",
+			object: "line_item",
+			want: url.Values{
+				"limit": {"100"},
+			},
+		},
+		{
+			name:    "subscription item",
+			hydrate: listSubscriptionItem,
+			quals: []*quals.Qual{
+				stringQual("subscription_id", "=", "sub_1"),
+			},
+			path:   "/v1/subscription_items",
+			object: "subscription_item",
+			want: url.Values{
+				"limit":        {"100"},
+				"subscription": {"sub_1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestQueryData(t, nil, tt.quals...)
+			if tt.object == "" {
+				d.backend.respond(tt.path, `{"id":"acct_1","object":"account"}`)
+			} else {
+				d.backend.respond(tt.path, listPage(tt.object, false, "obj_1"))
+			}
+
+			if _, err := tt.hydrate(testContext(), d.QueryData, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			requests := d.backend.requestsTo(tt.path)
+			if len(requests) != 1 {
+				t.Fatalf("got %d requests to %s, want 1 (all requests: %+v)", len(requests), tt.path, d.backend.requests)
+			}
+			if got := requests[0].Query; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got params %v, want %v", got, tt.want)
+			}
+			if len(d.items) != 1 {
+				t.Errorf("got %d items, want 1", len(d.items))
+			}
+		})
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	d := newTestQueryData(t, nil)
+	d.backend.respond("/v1/charges",
+		listPage("charge", true, "ch_1", "ch_2"),
+		listPage("charge", false, "ch_3"),
+	)
+
+	if _, err := listCharges(testContext(), d.QueryData, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	requests := d.backend.requestsTo("/v1/charges")
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	if got := requests[0].Query.Get("starting_after"); got != "" {
+		t.Errorf("first page requested starting after %q", got)
+	}
+	if got := requests[1].Query.Get("starting_after"); got != "ch_2" {
+		t.Errorf("second page requested starting after %q, want %q", got, "ch_2")
+	}
+	assertItemIds(t, d.items, "ch_1", "ch_2", "ch_3")
+}
+
+func TestSearchPagination(t *testing.T) {
+	d := newTestQueryData(t, nil, stringQual("name", "~~", "%Acme%"))
+	d.backend.respond("/v1/customers/search",
+		searchPage("customer", "page_2", "cus_1", "cus_2"),
+		searchPage("customer", "", "cus_3"),
+	)
+
+	if _, err := listCustomer(testContext(), d.QueryData, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	requests := d.backend.requestsTo("/v1/customers/search")
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	if got := requests[1].Query.Get("page"); got != "page_2" {
+		t.Errorf("second page requested page %q, want %q", got, "page_2")
+	}
+	assertItemIds(t, d.items, "cus_1", "cus_2", "cus_3")
+}
+
+func TestSearchTimestampClause(t *testing.T) {
+	created := searchField{Column: "created", Field: "created", Type: searchFieldTimestamp}
+	whole := &proto.QualValue{Value: &proto.QualValue_TimestampValue{TimestampValue: timestamppb.New(time.Unix(testStart, 0))}}
+	fractional := &proto.QualValue{Value: &proto.QualValue_TimestampValue{TimestampValue: timestamppb.New(time.Unix(testStart, 500000000))}}
+
+	tests := []struct {
+		operator string
+		value    *proto.QualValue
+		want     string
+	}{
+		{operator: "=", value: whole, want: "created:1700000000"},
+		{operator: "<>", value: whole, want: "-created:1700000000"},
+		{operator: ">", value: whole, want: "created>1700000000"},
+		{operator: ">=", value: whole, want: "created>=1700000000"},
+		{operator: "<", value: whole, want: "created<=1699999999"},
+		{operator: "<=", value: whole, want: "created<=1700000000"},
+		{operator: "=", value: fractional, want: ""},
+		{operator: "<>", value: fractional, want: ""},
+		{operator: ">", value: fractional, want: "created>1700000000"},
+		{operator: ">=", value: fractional, want: "created>=1700000001"},
+		{operator: "<", value: fractional, want: "created<=1700000000"},
+		{operator: "<=", value: fractional, want: "created<=1700000000"},
+	}
+
+	for _, tt := range tests {
+		if got := searchClause(created, tt.operator, tt.value); got != tt.want {
+			t.Errorf("got clause %q for %s %v, want %q", got, tt.operator, tt.value.GetTimestampValue().AsTime(), tt.want)
+		}
+	}
+}
+
+func TestListLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int64
+		wantLimit string
+		wantIds   []string
+	}{
+		{name: "below page size", limit: 2, wantLimit: "2", wantIds: []string{"ch_1", "ch_2"}},
+		{name: "above page size", limit: 500, wantLimit: "100", wantIds: []string{"ch_1", "ch_2", "ch_3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestQueryData(t, stripe.Int64(tt.limit))
+			d.backend.respond("/v1/charges",
+				listPage("charge", true, "ch_1", "ch_2", "ch_3"),
+				listPage("charge", false),
+			)
+
+			if _, err := listCharges(testContext(), d.QueryData, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			requests := d.backend.requestsTo("/v1/charges")
+			if got := requests[0].Query.Get("limit"); got != tt.wantLimit {
+				t.Errorf("requested limit %q, want %q", got, tt.wantLimit)
+			}
+			assertItemIds(t, d.items, tt.wantIds...)
+		})
+	}
+}
+
+func TestListConnectedAccount(t *testing.T) {
+	d := newTestQueryData(t, nil)
+	d.backend.respond("/v1/charges", listPage("charge", false, "ch_1"))
+
+	ctx := context.WithValue(testContext(), context_key.MatrixItem, map[string]interface{}{matrixKeyAccount: "acct_1", matrixKeyConnected: true})
+	if _, err := listCharges(ctx, d.QueryData, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	requests := d.backend.requestsTo("/v1/charges")
+	if len(requests) != 1 || requests[0].StripeAccount != "acct_1" {
+		t.Errorf("got requests %+v, want one request for account acct_1", requests)
+	}
+}
+
+func TestGetNotFound(t *testing.T) {
+	tests := []struct {
+		name    string
+		hydrate plugin.HydrateFunc
+		path    string
+	}{
+		{name: "balance transaction", hydrate: getBalanceTransaction, path: "/v1/balance_transactions/missing"},
+		{name: "charge", hydrate: getCharge, path: "/v1/charges/missing"},
+		{name: "checkout session", hydrate: getCheckoutSession, path: "/v1/checkout/sessions/missing"},
+		{name: "coupon", hydrate: getCoupon, path: "/v1/coupons/missing"},
+		{name: "credit note", hydrate: getCreditNote, path: "/v1/credit_notes/missing"},
+		{name: "customer", hydrate: getCustomer, path: "/v1/customers/missing"},
+		{name: "dispute", hydrate: getDispute, path: "/v1/disputes/missing"},
+		{name: "event", hydrate: getEvent, path: "/v1/events/missing"},
+		{name: "invoice", hydrate: getInvoice, path: "/v1/invoices/missing"},
+		{name: "invoice item", hydrate: getInvoiceItem, path: "/v1/invoiceitems/missing"},
+		{name: "payment intent", hydrate: getPaymentIntent, path: "/v1/payment_intents/missing"},
+		{name: "payment link", hydrate: getPaymentLink, path: "/v1/payment_links/missing"},
+		{name: "payout", hydrate: getPayout, path: "/v1/payouts/missing"},
+		{name: "plan", hydrate: getPlan, path: "/v1/plans/missing"},
+		{name: "price", hydrate: getPrice, path: "/v1/prices/missing"},
+		{name: "product", hydrate: getProduct, path: "/v1/products/missing"},
+		{name: "promotion code", hydrate: getPromotionCode, path: "/v1/promotion_codes/missing"},
+		{name: "refund", hydrate: getRefund, path: "/v1/refunds/missing"},
+		{name: "subscription", hydrate: getSubscription, path: "/v1/subscriptions/missing"},
+		{name: "webhook endpoint", hydrate: getWebhookEndpoint, path: "/v1/webhook_endpoints/missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestQueryData(t, nil, stringQual("id", "=", "missing"))
+
+			item, err := tt.hydrate(testContext(), d.QueryData, nil)
+			if !isNotFoundError(err) {
+				t.Fatalf("got item %v and error %v, want a not found error", item, err)
+			}
+			if requests := d.backend.requestsTo(tt.path); len(requests) != 1 {
+				t.Errorf("got %d requests to %s, want 1", len(requests), tt.path)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	d := newTestQueryData(t, nil, stringQual("id", "=", "ch_1"))
+	d.backend.respond("/v1/charges/ch_1", `{"id":"ch_1","object":"charge"}`)
+
+	item, err := getCharge(testContext(), d.QueryData, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if charge, ok := item.(*stripe.Charge); !ok || charge.ID != "ch_1" {
+		t.Errorf("got item %v, want charge ch_1", item)
+	}
+}
+
+// assertItemIds checks the ids of the streamed items.
+func assertItemIds(t *testing.T, items []interface{}, want ...string) {
+	t.Helper()
+	var got []string
+	for _, item := range items {
+		got = append(got, reflect.Indirect(reflect.ValueOf(item)).FieldByName("ID").String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got items %v, want %v", got, want)
+	}
+}
+
+func TestAPIVersion(t *testing.T) {
+	d := newTestQueryData(t, nil)
+	if got, _ := getApiVersion(testContext(), d.QueryData, nil); got != stripe.APIVersion {
+		t.Errorf("got default API version %v, want %v", got, stripe.APIVersion)
+	}
+
+	d.Connection.Config = stripeConfig{APIKey: stripe.String("sk_test_123"), APIVersion: stripe.String("2025-03-31.basil")}
+	if got, _ := getApiVersion(testContext(), d.QueryData, nil); got != "2025-03-31.basil" {
+		t.Errorf("got configured API version %v, want %v", got, "2025-03-31.basil")
+	}
+}
+
+func TestAPIVersionTransport(t *testing.T) {
+	var got string
+	transport := &apiVersionTransport{
+		version: "2025-03-31.basil",
+		base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			got = req.Header.Get("Stripe-Version")
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.stripe.com/v1/charges", nil)
+	req.Header.Set("Stripe-Version", stripe.APIVersion)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "2025-03-31.basil" {
+		t.Errorf("sent Stripe-Version %q, want %q", got, "2025-03-31.basil")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestVersionedColumns(t *testing.T) {
+	basil := stripeConfig{APIKey: stripe.String("sk_test_123"), APIVersion: stripe.String("2025-03-31.basil")}
+
+	t.Run("invoice subscription from parent", func(t *testing.T) {
+		d := newTestQueryData(t, nil)
+		d.Connection.Config = basil
+		d.backend.respond("/v1/invoices", `{"object":"list","has_more":false,"data":[
+			{"id":"in_1","object":"invoice","parent":{"subscription_details":{"subscription":"sub_1"}}},
+			{"id":"in_2","object":"invoice","parent":{"subscription_details":{"subscription":{"id":"sub_2","object":"subscription"}}}},
+			{"id":"in_3","object":"invoice","parent":null}
+		]}`)
+
+		if _, err := listInvoice(testContext(), d.QueryData, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		requests := d.backend.requestsTo("/v1/invoices")
+		if got := requests[0].Query["expand[2]"]; got != nil {
+			t.Errorf("expanded %v, which the basil API version does not have", got)
+		}
+		var got []string
+		for _, item := range d.items {
+			if s := item.(*stripe.Invoice).Subscription; s != nil {
+				got = append(got, s.ID)
+			} else {
+				got = append(got, "")
+			}
+		}
+		if want := []string{"sub_1", "sub_2", ""}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got subscriptions %v, want %v", got, want)
+		}
+	})
+
+	t.Run("subscription period from items", func(t *testing.T) {
+		d := newTestQueryData(t, nil, stringQual("id", "=", "sub_1"))
+		d.Connection.Config = basil
+		d.backend.respond("/v1/subscriptions/sub_1", `{"id":"sub_1","object":"subscription","items":{"object":"list","data":[
+			{"id":"si_1","object":"subscription_item","current_period_start":1700000000,"current_period_end":1702592000},
+			{"id":"si_2","object":"subscription_item","current_period_start":1699000000,"current_period_end":1701592000}
+		]}}`)
+
+		item, err := getSubscription(testContext(), d.QueryData, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		s := item.(*stripe.Subscription)
+		if s.CurrentPeriodStart != 1699000000 || s.CurrentPeriodEnd != 1702592000 {
+			t.Errorf("got period %d to %d, want %d to %d", s.CurrentPeriodStart, s.CurrentPeriodEnd, 1699000000, 1702592000)
+		}
+	})
+
+	t.Run("subscription item period", func(t *testing.T) {
+		d := newTestQueryData(t, nil, stringQual("subscription_id", "=", "sub_1"))
+		d.Connection.Config = basil
+		d.QueryContext.Columns = []string{"id", "current_period_start"}
+		d.backend.respond("/v1/subscription_items", `{"object":"list","has_more":false,"data":[
+			{"id":"si_1","object":"subscription_item","current_period_start":1700000000,"current_period_end":1702592000}
+		]}`)
+
+		if _, err := listSubscriptionItem(testContext(), d.QueryData, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if requests := d.backend.requestsTo("/v1/subscriptions/sub_1"); len(requests) != 0 {
+			t.Errorf("got %d requests for the subscription, want 0", len(requests))
+		}
+		item := d.items[0].(*subscriptionItem)
+		if item.CurrentPeriodStart != 1700000000 || item.CurrentPeriodEnd != 1702592000 {
+			t.Errorf("got period %d to %d, want %d to %d", item.CurrentPeriodStart, item.CurrentPeriodEnd, 1700000000, 1702592000)
+		}
+	})
+
+	t.Run("subscription item period before basil", func(t *testing.T) {
+		d := newTestQueryData(t, nil, stringQual("subscription_id", "=", "sub_1"))
+		d.QueryContext.Columns = []string{"id", "current_period_end"}
+		d.backend.respond("/v1/subscriptions/sub_1", `{"id":"sub_1","object":"subscription","current_period_start":1700000000,"current_period_end":1702592000}`)
+		d.backend.respond("/v1/subscription_items", listPage("subscription_item", false, "si_1"))
+
+		if _, err := listSubscriptionItem(testContext(), d.QueryData, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		item := d.items[0].(*subscriptionItem)
+		if item.CurrentPeriodStart != 1700000000 || item.CurrentPeriodEnd != 1702592000 {
+			t.Errorf("got period %d to %d, want %d to %d", item.CurrentPeriodStart, item.CurrentPeriodEnd, 1700000000, 1702592000)
+		}
+	})
+}
+
+func TestInvoiceLineItems(t *testing.T) {
+	t.Run("pagination", func(t *testing.T) {
+		d := newTestQueryData(t, nil, stringQual("invoice_id", "=", "in_1"))
+		d.backend.respond("/v1/invoices/in_1/lines",
+			`{"object":"list","has_more":true,"data":[{"id":"il_1","object":"line_item","price":{"id":"price_1"},"proration":true}]}`,
+			`{"object":"list","has_more":false,"data":[{"id":"il_2","object":"line_item","price":{"id":"price_2"}}]}`,
+		)
+
+		if _, err := listInvoiceLineItems(testContext(), d.QueryData, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		requests := d.backend.requestsTo("/v1/invoices/in_1/lines")
+		if len(requests) != 2 || requests[1].Query.Get("starting_after") != "il_1" {
+			t.Errorf("got requests %+v, want two pages", requests)
+		}
+		var got []invoiceLineItem
+		for _, item := range d.items {
+			got = append(got, *item.(*invoiceLineItem))
+		}
+		if len(got) != 2 || got[0].InvoiceID != "in_1" || got[0].PriceID != "price_1" || !got[0].Proration || got[1].PriceID != "price_2" || got[1].Proration {
+			t.Errorf("got line items %+v", got)
+		}
+	})
+
+	t.Run("basil", func(t *testing.T) {
+		d := newTestQueryData(t, nil, stringQual("invoice_id", "=", "in_1"))
+		d.Connection.Config = stripeConfig{APIKey: stripe.String("sk_test_123"), APIVersion: stripe.String("2025-03-31.basil")}
+		d.backend.respond("/v1/invoices/in_1/lines", `{"object":"list","has_more":false,"data":[{
+			"id":"il_1","object":"line_item",
+			"pricing":{"type":"price_details","price_details":{"price":"price_1","product":"prod_1"}},
+			"parent":{"type":"subscription_item_details","subscription_item_details":{"proration":true,"subscription":"sub_1"}},
+			"taxes":[{"amount":100,"type":"tax_rate_details"}]
+		}]}`)
+
+		if _, err := listInvoiceLineItems(testContext(), d.QueryData, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		item := d.items[0].(*invoiceLineItem)
+		if item.PriceID != "price_1" || !item.Proration || item.TaxAmounts == nil {
+			t.Errorf("got line item %+v, want the price, proration and taxes of the basil API version", item)
+		}
+	})
+}
+
+func TestInvoiceItems(t *testing.T) {
+	t.Run("pending", func(t *testing.T) {
+		d := newTestQueryData(t, nil)
+		d.backend.respond("/v1/invoiceitems", `{"object":"list","has_more":false,"data":[
+			{"id":"ii_1","object":"invoiceitem","price":{"id":"price_1"}},
+			{"id":"ii_2","object":"invoiceitem","invoice":"in_1","price":{"id":"price_2"}}
+		]}`)
+
+		if _, err := listInvoiceItems(testContext(), d.QueryData, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var got []invoiceItem
+		for _, item := range d.items {
+			got = append(got, *item.(*invoiceItem))
+		}
+		if len(got) != 2 || !got[0].Pending || got[0].PriceID != "price_1" || got[1].Pending || got[1].PriceID != "price_2" {
+			t.Errorf("got invoice items %+v, want ii_1 pending and ii_2 invoiced", got)
+		}
+	})
+
+	t.Run("basil", func(t *testing.T) {
+		d := newTestQueryData(t, nil, stringQual("id", "=", "ii_1"))
+		d.Connection.Config = stripeConfig{APIKey: stripe.String("sk_test_123"), APIVersion: stripe.String("2025-03-31.basil")}
+		d.backend.respond("/v1/invoiceitems/ii_1", `{
+			"id":"ii_1","object":"invoiceitem",
+			"pricing":{"type":"price_details","price_details":{"price":"price_1","product":"prod_1"}},
+			"parent":{"type":"subscription_details","subscription_details":{"subscription":"sub_1"}}
+		}`)
+
+		item, err := getInvoiceItem(testContext(), d.QueryData, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := item.(*invoiceItem); got.PriceID != "price_1" || got.InvoiceItem.Subscription == nil || got.InvoiceItem.Subscription.ID != "sub_1" {
+			t.Errorf("got invoice item %+v, want the price and subscription of the basil API version", got)
+		}
+	})
+}
+
+func TestUpcomingInvoice(t *testing.T) {
+	basil := stripeConfig{APIKey: stripe.String("sk_test_123"), APIVersion: stripe.String("2025-03-31.basil")}
+
+	t.Run("customer", func(t *testing.T) {
+		d := newTestQueryData(t, nil, stringQual("customer_id", "=", "cus_1"))
+		d.backend.respond("/v1/invoices/upcoming", `{"object":"invoice","customer":"cus_1","subscription":"sub_1","amount_due":1000}`)
+
+		if _, err := listInvoiceUpcoming(testContext(), d.QueryData, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		requests := d.backend.requestsTo("/v1/invoices/upcoming")
+		if len(requests) != 1 || requests[0].Method != http.MethodGet || requests[0].Query.Get("customer") != "cus_1" {
+			t.Errorf("got requests %+v, want one preview for cus_1", requests)
+		}
+		if len(d.items) != 1 {
+			t.Fatalf("got %d items, want 1", len(d.items))
+		}
+		if got := d.items[0].(*stripe.Invoice); got.AmountDue != 1000 || got.Subscription == nil || got.Subscription.ID != "sub_1" {
+			t.Errorf("got upcoming invoice %+v", got)
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		d := newTestQueryData(t, nil, stringQual("subscription_id", "=", "sub_1"))
+		d.backend.respondError("/v1/invoices/upcoming", &stripe.Error{
+			Code:           stripe.ErrorCodeInvoiceUpcomingNone,
+			HTTPStatusCode: http.StatusNotFound,
+			Msg:            "No upcoming invoices for customer: cus_1",
+			Type:           stripe.ErrorTypeInvalidRequest,
+		})
+
+		if _, err := listInvoiceUpcoming(testContext(), d.QueryData, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(d.items) != 0 {
+			t.Errorf("got %d items, want none for a subscription without an upcoming invoice", len(d.items))
+		}
+	})
+
+	t.Run("basil", func(t *testing.T) {
+		d := newTestQueryData(t, nil, stringQual("subscription_id", "=", "sub_1"))
+		d.Connection.Config = basil
+		d.backend.respond("/v1/invoices/create_preview", `{
+			"id":"upcoming_in_1","object":"invoice","customer":"cus_1",
+			"parent":{"type":"subscription_details","subscription_details":{"subscription":"sub_1"}}
+		}`)
+
+		if _, err := listInvoiceUpcoming(testContext(), d.QueryData, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		requests := d.backend.requestsTo("/v1/invoices/create_preview")
+		if len(requests) != 1 || requests[0].Method != http.MethodPost || requests[0].Query.Get("subscription") != "sub_1" {
+			t.Errorf("got requests %+v, want one preview for sub_1", requests)
+		}
+		if got := d.items[0].(*stripe.Invoice); got.Subscription == nil || got.Subscription.ID != "sub_1" {
+			t.Errorf("got upcoming invoice %+v, want the subscription of the basil API version", got)
+		}
+	})
+
+	t.Run("line items", func(t *testing.T) {
+		d := newTestQueryData(t, nil, stringQual("customer_id", "=", "cus_1"))
+		d.backend.respond("/v1/invoices/upcoming/lines",
+			`{"object":"list","has_more":true,"data":[{"id":"il_1","object":"line_item","price":{"id":"price_1"}}]}`,
+			`{"object":"list","has_more":false,"data":[{"id":"il_2","object":"line_item","price":{"id":"price_2"}}]}`,
+		)
+
+		if _, err := listInvoiceUpcomingLineItems(testContext(), d.QueryData, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		requests := d.backend.requestsTo("/v1/invoices/upcoming/lines")
+		if len(requests) != 2 || requests[0].Query.Get("customer") != "cus_1" || requests[1].Query.Get("starting_after") != "il_1" {
+			t.Errorf("got requests %+v, want two pages for cus_1", requests)
+		}
+		var got []string
+		for _, item := range d.items {
+			got = append(got, item.(*invoiceLineItem).PriceID)
+		}
+		if !reflect.DeepEqual(got, []string{"price_1", "price_2"}) {
+			t.Errorf("got line items with prices %v, want price_1 and price_2", got)
+		}
+		if got := columnValue(t, "stripe_invoice_upcoming_line_item", "invoice_id", d.items[0]); got != nil {
+			t.Errorf("got invoice_id %v, want nil before the basil API version", got)
+		}
+	})
+
+	t.Run("basil line items", func(t *testing.T) {
+		d := newTestQueryData(t, nil, stringQual("customer_id", "=", "cus_1"))
+		d.Connection.Config = basil
+		d.backend.respond("/v1/invoices/create_preview", `{"id":"upcoming_in_1","object":"invoice","customer":"cus_1"}`)
+		d.backend.respond("/v1/invoices/upcoming_in_1/lines", `{"object":"list","has_more":false,"data":[{
+			"id":"il_1","object":"line_item",
+			"pricing":{"type":"price_details","price_details":{"price":"price_1","product":"prod_1"}}
+		}]}`)
+
+		if _, err := listInvoiceUpcomingLineItems(testContext(), d.QueryData, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(d.items) != 1 {
+			t.Fatalf("got %d items, want 1", len(d.items))
+		}
+		if got := d.items[0].(*invoiceLineItem); got.InvoiceID != "upcoming_in_1" || got.PriceID != "price_1" {
+			t.Errorf("got line item %+v, want the line of the invoice preview", got)
+		}
+	})
+}
+
+func TestEventTypeWildcard(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{pattern: "invoice.%", want: "invoice.*"},
+		{pattern: "customer.subscription.%", want: "customer.subscription.*"},
+		{pattern: "charge.succeeded", want: "charge.succeeded"},
+		{pattern: "payment_intent.succeeded", want: ""},
+		{pattern: "payment_intent.%", want: ""},
+		{pattern: `payment\_intent.%`, want: ""},
+		{pattern: "invoice%", want: ""},
+		{pattern: "%.created", want: ""},
+		{pattern: "invoice.%.paid", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := eventTypeWildcard(tt.pattern); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookEndpoints(t *testing.T) {
+	d := newTestQueryData(t, nil)
+	d.backend.respond("/v1/webhook_endpoints", `{"object":"list","has_more":false,"data":[
+		{"id":"we_1","object":"webhook_endpoint","url":"https://example.com/hooks","enabled_events":["*"]},
+		{"id":"we_2","object":"webhook_endpoint","url":"http://example.com/hooks","enabled_events":["invoice.paid"],"api_version":"2020-08-27"}
+	]}`)
+
+	if _, err := listWebhookEndpoints(testContext(), d.QueryData, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []webhookEndpoint{
+		{UsesAccountDefaultAPIVersion: true, ListensToAllEvents: true, UsesHTTPS: true},
+		{UsesAccountDefaultAPIVersion: false, ListensToAllEvents: false, UsesHTTPS: false},
+	}
+	if len(d.items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(d.items), len(want))
+	}
+	for n, item := range d.items {
+		got := *item.(*webhookEndpoint)
+		got.WebhookEndpoint = nil
+		if got != want[n] {
+			t.Errorf("got derived columns %+v for item %d, want %+v", got, n, want[n])
+		}
+	}
+}
+
+func TestPaymentLinks(t *testing.T) {
+	d := newTestQueryData(t, nil)
+	d.backend.respond("/v1/payment_links", `{"object":"list","has_more":false,"data":[
+		{"id":"plink_1","object":"payment_link","active":true,"restrictions":{"completed_sessions":{"count":3,"limit":10}}},
+		{"id":"plink_2","object":"payment_link","active":true,"restrictions":null}
+	]}`)
+	d.backend.respond("/v1/payment_links/plink_1/line_items",
+		`{"object":"list","has_more":true,"data":[{"id":"li_1","object":"item","price":{"id":"price_1"},"quantity":1}]}`,
+		`{"object":"list","has_more":false,"data":[{"id":"li_2","object":"item","price":{"id":"price_2"},"quantity":2}]}`,
+	)
+
+	if _, err := listPaymentLinks(testContext(), d.QueryData, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(d.items) != 2 {
+		t.Fatalf("got %d items, want 2", len(d.items))
+	}
+	if got := d.items[0].(*paymentLink); got.Restrictions == nil {
+		t.Errorf("got no restrictions for plink_1, want them decoded from the response")
+	}
+	if got := d.items[1].(*paymentLink); got.Restrictions != nil {
+		t.Errorf("got restrictions %s for plink_2, want none", got.Restrictions)
+	}
+
+	items, err := listPaymentLinkLineItems(testContext(), d.QueryData, &plugin.HydrateData{Item: d.items[0]})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var prices []string
+	for _, item := range items.([]*stripe.LineItem) {
+		prices = append(prices, item.Price.ID)
+	}
+	if !reflect.DeepEqual(prices, []string{"price_1", "price_2"}) {
+		t.Errorf("got line items with prices %v, want price_1 and price_2", prices)
+	}
+}
+
+func TestPromotionCodeCoupon(t *testing.T) {
+	d := newTestQueryData(t, nil)
+	d.backend.respond("/v1/promotion_codes", `{"object":"list","has_more":false,"data":[
+		{"id":"promo_1","object":"promotion_code","code":"SUMMER","coupon":{"id":"co_1","object":"coupon"}},
+		{"id":"promo_2","object":"promotion_code","code":"WINTER","promotion":{"type":"coupon","coupon":"co_2"}},
+		{"id":"promo_3","object":"promotion_code","code":"SPRING","promotion":{"type":"coupon","coupon":{"id":"co_3","object":"coupon"}}}
+	]}`)
+
+	if _, err := listPromotionCodes(testContext(), d.QueryData, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, item := range d.items {
+		got = append(got, item.(*promotionCode).CouponID)
+	}
+	if want := []string{"co_1", "co_2", "co_3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got coupon IDs %v, want %v", got, want)
+	}
+}
+
+func TestCreditNoteTaxes(t *testing.T) {
+	basil := stripeConfig{APIKey: stripe.String("sk_test_123"), APIVersion: stripe.String("2025-03-31.basil")}
+
+	t.Run("credit note", func(t *testing.T) {
+		d := newTestQueryData(t, nil, stringQual("id", "=", "cn_1"))
+		d.Connection.Config = basil
+		d.backend.respond("/v1/credit_notes/cn_1", `{"id":"cn_1","object":"credit_note","total_taxes":[{"amount":100,"type":"tax_rate_details"}]}`)
+
+		item, err := getCreditNote(testContext(), d.QueryData, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := item.(*creditNote); got.CreditNote.ID != "cn_1" || got.TaxAmounts == nil {
+			t.Errorf("got credit note %+v, want the total taxes of the basil API version", got)
+		}
+	})
+
+	t.Run("line items", func(t *testing.T) {
+		d := newTestQueryData(t, nil, stringQual("credit_note_id", "=", "cn_1"))
+		d.backend.respond("/v1/credit_notes/cn_1/lines", `{"object":"list","has_more":false,"data":[
+			{"id":"cnli_1","object":"credit_note_line_item","tax_amounts":[{"amount":100}]},
+			{"id":"cnli_2","object":"credit_note_line_item","taxes":[{"amount":200,"type":"tax_rate_details"}]}
+		]}`)
+
+		if _, err := listCreditNoteLineItems(testContext(), d.QueryData, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, item := range d.items {
+			if got := item.(*creditNoteLineItem); got.CreditNoteID != "cn_1" || got.TaxAmounts == nil {
+				t.Errorf("got line item %+v, want its credit note and taxes", got)
+			}
+		}
+	})
+}
+
+func TestSubscriptionItemErrors(t *testing.T) {
+	d := newTestQueryData(t, nil, stringQual("subscription_id", "=", "sub_1"))
+	d.backend.respondError("/v1/subscription_items", &stripe.Error{HTTPStatusCode: http.StatusInternalServerError, Type: stripe.ErrorTypeAPI})
+
+	if _, err := listSubscriptionItem(testContext(), d.QueryData, nil); err == nil {
+		t.Errorf("got no error listing subscription items, want the API error")
+	}
+
+	d.backend.respondError("/v1/subscription_items/si_1/usage_record_summaries", &stripe.Error{HTTPStatusCode: http.StatusInternalServerError, Type: stripe.ErrorTypeAPI})
+	item := &subscriptionItem{SubscriptionItem: &stripe.SubscriptionItem{ID: "si_1"}}
+	if _, err := listUsageRecordSummaries(testContext(), d.QueryData, &plugin.HydrateData{Item: item}); err == nil {
+		t.Errorf("got no error listing usage record summaries, want the API error")
+	}
+}
+
+func TestParentObjectInOtherAccount(t *testing.T) {
+	tests := []struct {
+		name    string
+		hydrate plugin.HydrateFunc
+		qual    *quals.Qual
+		path    string
+		body    string
+	}{
+		{
+			name:    "subscription item",
+			hydrate: listSubscriptionItem,
+			qual:    stringQual("subscription_id", "=", "sub_1"),
+			path:    "/v1/subscription_items",
+			body:    listPage("subscription_item", false, "si_1"),
+		},
+		{
+			name:    "invoice line item",
+			hydrate: listInvoiceLineItems,
+			qual:    stringQual("invoice_id", "=", "in_1"),
+			path:    "/v1/invoices/in_1/lines",
+			body:    listPage("line_item", false, "il_1"),
+		},
+		{
+			name:    "credit note line item",
+			hydrate: listCreditNoteLineItems,
+			qual:    stringQual("credit_note_id", "=", "cn_1"),
+			path:    "/v1/credit_notes/cn_1/lines",
+			body:    listPage("credit_note_line_item", false, "cnli_1"),
+		},
+		{
+			name:    "checkout session line item",
+			hydrate: listCheckoutSessionLineItems,
+			qual:    stringQual("session_id", "=", "cs_1"),
+			path:    "/v1/checkout/sessions/cs_1/line_items",
+			body:    listPage("item", false, "li_1"),
+		},
+		{
+			name:    "upcoming invoice",
+			hydrate: listInvoiceUpcoming,
+			qual:    stringQual("customer_id", "=", "cus_1"),
+			path:    "/v1/invoices/upcoming",
+			body:    `{"object":"invoice","customer":"cus_1"}`,
+		},
+		{
+			name:    "upcoming invoice line item",
+			hydrate: listInvoiceUpcomingLineItems,
+			qual:    stringQual("customer_id", "=", "cus_1"),
+			path:    "/v1/invoices/upcoming/lines",
+			body:    listPage("line_item", false, "il_1"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestQueryData(t, nil, tt.qual)
+			d.backend.respondForAccount("acct_2", tt.path, tt.body)
+
+			// The parent object only exists in acct_2, so acct_1 returns
+			// resource_missing
+			for _, account := range []string{"acct_1", "acct_2"} {
+				ctx := context.WithValue(testContext(), context_key.MatrixItem, map[string]interface{}{matrixKeyAccount: account, matrixKeyConnected: true})
+				if _, err := tt.hydrate(ctx, d.QueryData, nil); err != nil {
+					t.Fatalf("got error %v listing in %s, want no error", err, account)
+				}
+			}
+
+			if len(d.items) != 1 {
+				t.Errorf("got %d items, want the item of acct_2", len(d.items))
+			}
+			if requests := d.backend.requestsTo(tt.path); len(requests) != 2 {
+				t.Errorf("got requests %+v, want one request per account", requests)
+			}
+		})
+	}
+}
+
+func TestPermissionErrors(t *testing.T) {
+	d := newTestQueryData(t, nil)
+	d.backend.respondError("/v1/charges", permissionError("rak_charge_read"))
+
+	_, err := listCharges(testContext(), d.QueryData, nil)
+	if !isPermissionError(err) || isNotFoundError(err) {
+		t.Fatalf("got error %v, want a permission error", err)
+	}
+	if !shouldIgnorePermissionError(testContext(), d.QueryData, nil, err) {
+		t.Errorf("permission error is not ignored by default")
+	}
+	if !shouldIgnoreGetError(testContext(), d.QueryData, nil, err) {
+		t.Errorf("permission error is not ignored by get calls by default")
+	}
+
+	d.Connection.Config = stripeConfig{APIKey: stripe.String("rk_test_123"), IgnorePermissionErrors: stripe.Bool(false)}
+	if shouldIgnorePermissionError(testContext(), d.QueryData, nil, err) {
+		t.Errorf("permission error is ignored with ignore_permission_errors = false")
+	}
+	if shouldIgnoreGetError(testContext(), d.QueryData, nil, err) {
+		t.Errorf("permission error is ignored by get calls with ignore_permission_errors = false")
+	}
+
+	notFound := &stripe.Error{Code: stripe.ErrorCodeResourceMissing, HTTPStatusCode: http.StatusNotFound}
+	if shouldIgnorePermissionError(testContext(), d.QueryData, nil, notFound) {
+		t.Errorf("not found error is ignored by list calls")
+	}
+	if !shouldIgnoreGetError(testContext(), d.QueryData, nil, notFound) {
+		t.Errorf("not found error is not ignored by get calls")
+	}
+}
+
+func TestKeyPermissions(t *testing.T) {
+	d := newTestQueryData(t, nil)
+	d.backend.respond("/v1/account", `{"id":"acct_1","object":"account"}`)
+	for _, p := range keyPermissionProbes {
+		if p.Endpoint != "/v1/account" {
+			d.backend.respond(p.Endpoint, listPage("", false))
+		}
+	}
+	d.backend.respondError("/v1/charges", permissionError("rak_charge_read"))
+
+	if _, err := listKeyPermissions(testContext(), d.QueryData, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(d.items) != len(keyPermissionProbes) {
+		t.Fatalf("got %d rows, want %d", len(d.items), len(keyPermissionProbes))
+	}
+	for _, item := range d.items {
+		p := item.(keyPermission)
+		if want := p.TableName != "stripe_charge"; p.CanRead != want {
+			t.Errorf("got can_read %t for %s, want %t", p.CanRead, p.TableName, want)
+		}
+		if !p.CanRead && p.Error == "" {
+			t.Errorf("got no error for %s", p.TableName)
+		}
+		if got := columnValue(t, "stripe_key_permission", "can_read", p); got != p.CanRead {
+			t.Errorf("got can_read column %v for %s, want %t", got, p.TableName, p.CanRead)
+		}
+	}
+	for _, r := range d.backend.requestsTo("/v1/charges") {
+		if got := r.Query.Get("limit"); got != "1" {
+			t.Errorf("probed with limit %q, want %q", got, "1")
+		}
+	}
+}
+
+// columnValue returns the value of a column of a table for an item, as
+// transformed for the query results.
+func columnValue(t *testing.T, tableName, columnName string, item interface{}) interface{} {
+	t.Helper()
+	p := Plugin(context.Background())
+	table := p.TableMap[tableName]
+	for _, column := range table.Columns {
+		if column.Name != columnName {
+			continue
+		}
+		transforms := column.Transform
+		if transforms == nil {
+			transforms = p.DefaultTransform
+		}
+		value, err := transforms.Execute(testContext(), &transform.TransformData{HydrateItem: item, ColumnName: columnName})
+		if err != nil {
+			t.Fatalf("unexpected error transforming %s.%s: %v", tableName, columnName, err)
+		}
+		return value
+	}
+	t.Fatalf("table %s has no column %s", tableName, columnName)
+	return nil
+}
+
+func TestKeyPermissionProbesCoverTables(t *testing.T) {
+	probed := map[string]bool{}
+	for _, p := range keyPermissionProbes {
+		probed[p.TableName] = true
+	}
+	for name := range Plugin(context.Background()).TableMap {
+		if name != "stripe_key_permission" && !probed[name] {
+			t.Errorf("table %s has no key permission probe", name)
+		}
+	}
+}
+
+func TestAccountMatrix(t *testing.T) {
+	t.Run("platform account without account_id filter", func(t *testing.T) {
+		d := newTestQueryData(t, nil)
+		if matrix := connectedAccountMatrix(testContext(), d.QueryData); matrix != nil {
+			t.Errorf("got matrix %v, want nil", matrix)
+		}
+		if requests := d.backend.requestsTo("/v1/account"); len(requests) != 0 {
+			t.Errorf("got %d requests for the account, want 0", len(requests))
+		}
+	})
+
+	t.Run("platform account with account_id filter", func(t *testing.T) {
+		d := newTestQueryData(t, nil)
+		d.QueryContext.UnsafeQuals = map[string]*proto.Quals{
+			"account_id": {Quals: []*proto.Qual{{FieldName: "account_id", Operator: &proto.Qual_StringValue{StringValue: "="}, Value: &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: "acct_2"}}}}},
+		}
+		d.backend.respond("/v1/account", `{"id":"acct_1","object":"account"}`)
+
+		matrix := connectedAccountMatrix(testContext(), d.QueryData)
+		want := []map[string]interface{}{{matrixKeyAccount: "acct_1", matrixKeyConnected: false}}
+		if !reflect.DeepEqual(matrix, want) {
+			t.Fatalf("got matrix %v, want %v", matrix, want)
+		}
+		ctx := context.WithValue(testContext(), context_key.MatrixItem, matrix[0])
+		if id := connectedAccount(ctx); id != nil {
+			t.Errorf("got connected account %s for the platform account, want nil", *id)
+		}
+	})
+
+	t.Run("platform account is retrieved once", func(t *testing.T) {
+		d := newTestQueryData(t, nil)
+		d.QueryContext.UnsafeQuals = map[string]*proto.Quals{
+			"account_id": {Quals: []*proto.Qual{{FieldName: "account_id", Operator: &proto.Qual_StringValue{StringValue: "="}, Value: &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: "acct_2"}}}}},
+		}
+		d.backend.respond("/v1/account", `{"id":"acct_1","object":"account"}`)
+
+		// The platform_account_id connection key column resolves the account
+		// before the tables of the connection are queried
+		if _, err := getPlatformAccountId(testContext(), d.QueryData, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for n := 0; n < 2; n++ {
+			connectedAccountMatrix(testContext(), d.QueryData)
+		}
+		if requests := d.backend.requestsTo("/v1/account"); len(requests) != 1 {
+			t.Errorf("got %d requests for the account, want 1", len(requests))
+		}
+	})
+
+	t.Run("connected accounts are pruned without calling the API", func(t *testing.T) {
+		d := newTestQueryData(t, nil)
+		d.Connection.Config = stripeConfig{APIKey: stripe.String("sk_test_123"), ConnectedAccountIds: []string{"acct_2", "acct_3"}}
+		d.QueryContext.UnsafeQuals = map[string]*proto.Quals{
+			"account_id": {Quals: []*proto.Qual{{FieldName: "account_id", Operator: &proto.Qual_StringValue{StringValue: "="}, Value: &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: "acct_9"}}}}},
+		}
+
+		connectedAccountMatrix(testContext(), d.QueryData)
+		if len(d.backend.requests) != 0 {
+			t.Errorf("got requests %+v, want none", d.backend.requests)
+		}
+	})
+
+	t.Run("connected accounts", func(t *testing.T) {
+		d := newTestQueryData(t, nil)
+		d.Connection.Config = stripeConfig{APIKey: stripe.String("sk_test_123"), ConnectedAccountIds: []string{"acct_2", "acct_3"}}
+
+		matrix := connectedAccountMatrix(testContext(), d.QueryData)
+		want := []map[string]interface{}{
+			{matrixKeyAccount: "acct_2", matrixKeyConnected: true},
+			{matrixKeyAccount: "acct_3", matrixKeyConnected: true},
+		}
+		if !reflect.DeepEqual(matrix, want) {
+			t.Errorf("got matrix %v, want %v", matrix, want)
+		}
+	})
+}
+
+func TestAccountMatrixError(t *testing.T) {
+	d := newTestQueryData(t, nil)
+	d.Connection.Config = stripeConfig{APIKey: stripe.String("sk_test_123"), ConnectedAccountIds: []string{"acct_*"}}
+	d.backend.respondError("/v1/accounts", permissionError("rak_connected_account_read"))
+
+	matrix := connectedAccountMatrix(testContext(), d.QueryData)
+	if len(matrix) != 1 {
+		t.Fatalf("got matrix %v, want a single item holding the error", matrix)
+	}
+	ctx := context.WithValue(testContext(), context_key.MatrixItem, matrix[0])
+	_, err := listCharges(ctx, d.QueryData, nil)
+	if err == nil {
+		t.Fatalf("got no error listing charges, want the error listing connected accounts")
+	}
+	if shouldIgnorePermissionError(ctx, d.QueryData, nil, err) {
+		t.Errorf("error listing connected accounts is ignored as a permission error of the table")
+	}
+	if requests := d.backend.requestsTo("/v1/charges"); len(requests) != 0 {
+		t.Errorf("got %d requests for charges, want 0", len(requests))
+	}
+}
+
+func TestLivemode(t *testing.T) {
+	tests := map[string]bool{
+		"sk_live_123": true,
+		"rk_live_123": true,
+		"sk_test_123": false,
+		"rk_test_123": false,
+	}
+	for key, want := range tests {
+		d := newTestQueryData(t, nil)
+		d.Connection.Config = stripeConfig{APIKey: stripe.String(key)}
+		if got, _ := getLivemode(testContext(), d.QueryData, nil); got != want {
+			t.Errorf("got livemode %v for %s, want %v", got, key, want)
+		}
+	}
+}
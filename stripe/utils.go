@@ -3,7 +3,9 @@ package stripe
 import (
 	"context"
 	"errors"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/stripe/stripe-go/v76"
 	"github.com/stripe/stripe-go/v76/client"
@@ -11,7 +13,12 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 )
 
-func connect(_ context.Context, d *plugin.QueryData) (*client.API, error) {
+func connect(ctx context.Context, d *plugin.QueryData) (*client.API, error) {
+	// Every hydrate function connects first, so it fails with the error of
+	// its matrix item
+	if err := matrixError(ctx); err != nil {
+		return nil, err
+	}
 
 	// Load connection from cache, which preserves throttling protection etc
 	cacheKey := "stripe"
@@ -25,33 +32,72 @@ func connect(_ context.Context, d *plugin.QueryData) (*client.API, error) {
 		URL:  "https://hub.steampipe.io/plugins/turbot/stripe",
 	})
 
-	// Default to using env vars
-	apiKey := os.Getenv("STRIPE_API_KEY")
-
-	// But prefer the config
 	stripeConfig := GetConfig(d.Connection)
-	if stripeConfig.APIKey != nil {
-		apiKey = *stripeConfig.APIKey
-	}
-
+	apiKey := getAPIKey(d)
 	if apiKey == "" {
 		// Credentials not set
 		return nil, errors.New("api_key must be configured")
 	}
 
-	maxRetries := int64(10)
-	config := &stripe.BackendConfig{
-		MaxNetworkRetries: &maxRetries,
-	}
-
 	conn := &client.API{}
-	conn.Init(apiKey, &stripe.Backends{
-		API:     stripe.GetBackendWithConfig(stripe.APIBackend, config),
-		Uploads: stripe.GetBackendWithConfig(stripe.UploadsBackend, config),
-	})
+	conn.Init(apiKey, newBackends(stripeConfig, getRequestLimiter(d)))
 
 	// Save to cache
 	d.ConnectionManager.Cache.Set(cacheKey, conn)
 
 	return conn, nil
 }
+
+// getAPIKey returns the API key of the connection. It defaults to the
+// STRIPE_API_KEY env var, but prefers the config.
+func getAPIKey(d *plugin.QueryData) string {
+	apiKey := os.Getenv("STRIPE_API_KEY")
+	stripeConfig := GetConfig(d.Connection)
+	if stripeConfig.APIKey != nil {
+		apiKey = *stripeConfig.APIKey
+	}
+	return apiKey
+}
+
+// newBackends returns the backends used to call the Stripe API. It is a
+// variable so tests can replace the backends with fakes that serve canned
+// responses.
+var newBackends = func(stripeConfig stripeConfig, limiter *requestLimiter) *stripe.Backends {
+	// stripe-go sends the API version it is pinned to, so a configured version
+	// replaces it on each request
+	var transport http.RoundTripper = http.DefaultTransport
+	if stripeConfig.APIVersion != nil && *stripeConfig.APIVersion != "" {
+		transport = &apiVersionTransport{
+			version: *stripeConfig.APIVersion,
+			base:    transport,
+		}
+	}
+
+	// The timeout matches the stripe-go default
+	httpClient := &http.Client{
+		Timeout: 80 * time.Second,
+		Transport: &rateLimitTransport{
+			limiter: limiter,
+			base:    transport,
+		},
+	}
+
+	// Each backend needs its own config, since GetBackendWithConfig sets the
+	// default URL on the config it is given. A nil URL uses the Stripe default.
+	retries := maxRetries(stripeConfig)
+	apiConfig := &stripe.BackendConfig{
+		HTTPClient:        httpClient,
+		MaxNetworkRetries: &retries,
+		URL:               stripeConfig.APIBaseURL,
+	}
+	uploadsConfig := &stripe.BackendConfig{
+		HTTPClient:        httpClient,
+		MaxNetworkRetries: &retries,
+		URL:               stripeConfig.UploadsBaseURL,
+	}
+
+	return &stripe.Backends{
+		API:     stripe.GetBackendWithConfig(stripe.APIBackend, apiConfig),
+		Uploads: stripe.GetBackendWithConfig(stripe.UploadsBackend, uploadsConfig),
+	}
+}